@@ -23,7 +23,7 @@ func (a *alias) Retrieve(ctx context.Context) (*api.CollectionAlias, error) {
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -34,7 +34,7 @@ func (a *alias) Delete(ctx context.Context) (*api.CollectionAlias, error) {
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -24,7 +24,7 @@ func (a *aliases) Upsert(ctx context.Context, aliasName string, aliasSchema *api
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -35,7 +35,7 @@ func (a *aliases) Retrieve(ctx context.Context) ([]*api.CollectionAlias, error)
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200.Aliases, nil
 }
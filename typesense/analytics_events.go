@@ -21,7 +21,7 @@ func (a *analyticsEvents) Create(ctx context.Context, eventSchema *api.Analytics
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -32,7 +32,7 @@ func (a *analyticsEvents) Retrieve(ctx context.Context, params *api.GetAnalytics
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -23,7 +23,7 @@ func (a *analyticsRule) Delete(ctx context.Context) (*api.AnalyticsRule, error)
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -34,7 +34,7 @@ func (a *analyticsRule) Retrieve(ctx context.Context) (*api.AnalyticsRule, error
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -45,7 +45,7 @@ func (a *analyticsRule) Update(ctx context.Context, ruleSchema *api.AnalyticsRul
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
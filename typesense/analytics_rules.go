@@ -46,7 +46,7 @@ func (a *analyticsRules) Create(ctx context.Context, rules []*api.AnalyticsRuleC
 	}
 
 	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
-		return nil, &HTTPError{Status: httpResp.StatusCode, Body: responseBody}
+		return nil, newHTTPError(httpResp, responseBody)
 	}
 
 	// Parse the response manually since the generated union type has issues
@@ -79,7 +79,7 @@ func (a *analyticsRules) Retrieve(ctx context.Context) ([]*api.AnalyticsRule, er
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 
 	// Convert []AnalyticsRule to []*AnalyticsRule
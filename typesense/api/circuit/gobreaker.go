@@ -106,6 +106,11 @@ func NewGoBreaker(opts ...GoBreakerOption) *GoBreaker {
 	return gb
 }
 
+// State returns the current state of the CircuitBreaker.
+func (gb *GoBreaker) State() gobreaker.State {
+	return gb.cb.State()
+}
+
 func (gb *GoBreaker) Execute(req func() error) error {
 	_, err := gb.cb.Execute(func() (interface{}, error) {
 		return nil, req()
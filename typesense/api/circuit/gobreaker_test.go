@@ -29,6 +29,20 @@ func TestGoBreakerExecuteReturnsError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGoBreakerState(t *testing.T) {
+	gb := NewGoBreaker(WithGoBreakerReadyToTrip(func(counts gobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= 2
+	}))
+	assert.Equal(t, gobreaker.StateClosed, gb.State())
+
+	for i := 0; i < 2; i++ {
+		_ = gb.Execute(func() error {
+			return errors.New("execute error")
+		})
+	}
+	assert.Equal(t, gobreaker.StateOpen, gb.State())
+}
+
 func TestGoBreakerSettingsOptions(t *testing.T) {
 	readyToTrip := func(counts gobreaker.Counts) bool {
 		return counts.Requests > 10 &&
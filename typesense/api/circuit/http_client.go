@@ -12,6 +12,13 @@ type HTTPRequestDoer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// HTTPRequestDoerFunc is an adapter to allow the use of ordinary functions as HTTPRequestDoer.
+type HTTPRequestDoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f HTTPRequestDoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 // Breaker defines contract for circuit breakers to implement
 type Breaker interface {
 	Execute(req func() error) error
@@ -0,0 +1,12 @@
+package circuit
+
+// NoopBreaker is a Breaker that never trips. It can be used to disable
+// circuit breaking entirely.
+type NoopBreaker struct{}
+
+// assert that NoopBreaker implements CircuitBreaker interface
+var _ Breaker = NoopBreaker{}
+
+func (NoopBreaker) Execute(req func() error) error {
+	return req()
+}
@@ -0,0 +1,30 @@
+package circuit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoopBreakerExecute(t *testing.T) {
+	breaker := NoopBreaker{}
+	i := 0
+	err := breaker.Execute(func() error {
+		i++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+}
+
+func TestNoopBreakerNeverTrips(t *testing.T) {
+	breaker := NoopBreaker{}
+	requestErr := errors.New("execute error")
+	for i := 0; i < 1000; i++ {
+		err := breaker.Execute(func() error {
+			return requestErr
+		})
+		assert.ErrorIs(t, err, requestErr)
+	}
+}
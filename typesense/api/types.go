@@ -5,6 +5,8 @@ type ImportDocumentResponse struct {
 	Error    string `json:"error"`
 	Document any    `json:"document"` // on success: map[string]interface{}; on error: string
 	Id       string `json:"id"`
+	// Code is the HTTP status code of a failed document, e.g. 409 if it already exists.
+	Code int `json:"code,omitempty"`
 }
 
 type StemmingDictionaryWord struct {
@@ -1,32 +1,61 @@
 package typesense
 
 import (
-	"bytes"
 	"context"
 	"errors"
-	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/typesense/typesense-go/v4/typesense/api/circuit"
 )
 
+// APICall is safe for concurrent use by multiple goroutines. Node health
+// bookkeeping only uses atomic operations, so concurrent requests never
+// wait on each other.
 type APICall struct {
 	client               circuit.HTTPRequestDoer
 	nearestNode          *Node
-	nodes                []Node
-	currentNodeIndex     int
+	nodes                []*Node
+	server               *Node
+	nodeSelector         NodeSelector
 	healthcheckInterval  time.Duration
 	numRetriesPerRequest int
-	retryInterval        time.Duration
+	retryPolicy          RetryPolicy
+	maxRetryAfter        time.Duration
+	isIdempotent         IdempotencyClassifier
+	retryNonIdempotent   bool
+	hedgeDelay           time.Duration
+	throttledResponses   atomic.Uint64
+	retryAfterWaits      atomic.Uint64
+	retryAfterWaitTime   atomic.Int64
+	stopHealthchecks     func()
+	logger               *slog.Logger
+}
+
+// ThrottlingStats counts the responses with which the server asked the client to slow down.
+type ThrottlingStats struct {
+	// ThrottledResponses is the number of 429 responses and 503 responses
+	// carrying a Retry-After header.
+	ThrottledResponses uint64
+	// RetryAfterWaits is the number of times a retry was delayed according
+	// to the Retry-After header.
+	RetryAfterWaits uint64
+	// RetryAfterWaitTime is the total time spent waiting because of Retry-After headers.
+	RetryAfterWaitTime time.Duration
 }
 
 type Node struct {
-	isHealthy           bool
+	isHealthy           atomic.Bool
 	index               interface{}
 	url                 string
-	lastAccessTimestamp int64
+	lastAccessTimestamp atomic.Int64
+	healthcheckInterval time.Duration
+	outstanding         atomic.Int64
+	breaker             circuit.Breaker
+	probe               nodeProbe
 }
 
 var apiCallTimeNow = time.Now // for test stubbing
@@ -40,11 +69,28 @@ type APICallOption func(*APICall)
 
 func NewAPICall(client circuit.HTTPRequestDoer, config *ClientConfig) *APICall {
 	apiCall := &APICall{
-		currentNodeIndex:     -1,
 		healthcheckInterval:  config.HealthcheckInterval,
 		client:               client,
+		nodeSelector:         config.NodeSelector,
 		numRetriesPerRequest: config.NumRetries,
-		retryInterval:        config.RetryInterval,
+		retryPolicy:          config.RetryPolicy,
+		maxRetryAfter:        config.MaxRetryAfter,
+		isIdempotent:         config.IdempotencyClassifier,
+		retryNonIdempotent:   config.RetryNonIdempotentRequests,
+		hedgeDelay:           config.HedgeDelay,
+		logger:               config.Logger,
+	}
+	if apiCall.nodeSelector == nil {
+		apiCall.nodeSelector = NewRoundRobinSelector()
+	}
+	if apiCall.retryPolicy == nil {
+		apiCall.retryPolicy = &FixedIntervalRetryPolicy{Interval: config.RetryInterval}
+	}
+	if apiCall.maxRetryAfter <= 0 {
+		apiCall.maxRetryAfter = defaultMaxRetryAfter
+	}
+	if apiCall.isIdempotent == nil {
+		apiCall.isIdempotent = DefaultIdempotencyClassifier
 	}
 
 	// default numRetries is the number of nodes (+1 if nearestNode is specified)
@@ -57,96 +103,226 @@ func NewAPICall(client circuit.HTTPRequestDoer, config *ClientConfig) *APICall {
 
 	apiCall.initializeNodesMetadata(config)
 
+	// without nodes, requests are made to the server URL of the API client and
+	// retried on it only if a number of retries is configured
+	if len(apiCall.nodes) == 0 {
+		apiCall.server = &Node{index: "server", url: config.ServerURL, healthcheckInterval: apiCall.healthcheckInterval}
+		setNodeHealthCheck(apiCall.server, HEALTHY)
+		apiCall.numRetriesPerRequest = max(apiCall.numRetriesPerRequest, 1)
+	}
+
+	if config.HealthProbeInterval > 0 && len(apiCall.allNodes()) > 0 {
+		apiCall.startHealthchecks(config.HealthProbeInterval)
+	}
+
 	return apiCall
 }
 
 func (a *APICall) Do(req *http.Request) (*http.Response, error) {
-	// Default is to not load balance for backward compatibility
-	if len(a.nodes) == 0 {
-		res, err := a.client.Do(req)
-		return res, err
+	response, attempts, err := a.do(req)
+	if isFailedResponse(response, err) && req.Context().Err() == nil {
+		a.log(req.Context(), slog.LevelError, "typesense request failed",
+			append([]any{slog.Int("attempts", attempts), slog.Any("request", requestLogValue{req})},
+				failureLogAttrs(response, err)...)...)
 	}
+	if response != nil && response.Request != nil {
+		// make the number of attempts available to errors created from the response
+		response.Request = response.Request.WithContext(
+			context.WithValue(response.Request.Context(), attemptsContextKey{}, attempts))
+	}
+	return response, err
+}
 
+func (a *APICall) do(req *http.Request) (*http.Response, int, error) {
 	var lastResponse *http.Response
 	var lastError error
-	var bodyBytes []byte
 
-	if req.GetBody != nil {
-		// Store body in case we need to retry
-		reqBody, err := req.GetBody()
-		if err != nil {
-			return nil, err
+	ctx := req.Context()
+	startTime := time.Now()
+	replayable := a.retryNonIdempotent || a.isIdempotent(req)
+	hedgeDelay := a.hedgeDelayFor(req)
+
+	attempts := 0
+	retryReason := ""
+	for numTries := 0; numTries < a.numRetriesPerRequest; numTries++ {
+		attempts++
+		info := AttemptInfo{Number: attempts, RetryReason: retryReason}
+		var node *Node
+		var response *http.Response
+		var err error
+		if hedgeDelay > 0 {
+			node, response, err = a.doHedged(req, hedgeDelay, info)
+		} else {
+			// Default is to not load balance for backward compatibility
+			if a.server != nil {
+				node = a.server
+				info.NodeURL = req.URL.Scheme + "://" + req.URL.Host
+			} else {
+				node = a.getNextNode()
+				replaceRequestHostname(req, node.url)
+				info.NodeURL = node.url
+			}
+
+			if err := rewindBody(req); err != nil {
+				closeResponseBody(lastResponse)
+				return nil, attempts, err
+			}
+
+			response, err = a.doWithNode(node, withAttemptInfo(req, info))
 		}
-		defer reqBody.Close()
 
-		bodyBytes, err = io.ReadAll(reqBody)
-		if err != nil {
-			return nil, err
+		// return early if request is aborted
+		if errors.Is(err, context.Canceled) || (err != nil && ctx.Err() != nil) {
+			closeResponseBody(lastResponse)
+			return nil, attempts, err
 		}
-	}
 
-	for numTries := 0; numTries < a.numRetriesPerRequest; numTries++ {
-		node := a.getNextNode()
+		if err == nil && response.StatusCode >= 1 && response.StatusCode <= 499 {
+			// Treat any status code > 0 and < 500 to be an indication that node is healthy
+			// We exclude 0 since some clients return 0 when request fails
+			a.setNodeHealth(ctx, node, HEALTHY, response, err)
+		} else if err != nil || response.StatusCode >= 500 {
+			// If connection timeouts or status 5xx, the node is unhealthy
+			a.setNodeHealth(ctx, node, UNHEALTHY, response, err)
+		} else {
+			// the response of status 0 is not returned, so its body is closed here
+			closeResponseBody(response)
+			continue
+		}
 
-		replaceRequestHostname(req, node.url)
+		throttled := isThrottled(response)
+		if throttled {
+			a.throttledResponses.Add(1)
+		}
 
-		if bodyBytes != nil {
-			// Create a new io.ReadCloser for each retry
-			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		// a write that may have been applied must not be replayed on another
+		// node, and an overloaded server may be backed off from by the caller
+		if (!replayable && !isRequestUnprocessed(response, err)) || isOverloaded(ctx, response) {
+			closeResponseBody(lastResponse)
+			return response, attempts, err
 		}
 
-		response, err := a.client.Do(req)
+		wait, retry := a.retryPolicy.NextRetry(&RetryAttempt{
+			Attempt:  numTries + 1,
+			Elapsed:  time.Since(startTime),
+			Response: response,
+			Err:      err,
+		})
+		if !retry {
+			closeResponseBody(lastResponse)
+			return response, attempts, err
+		}
 
-		// return early if request is aborted
-		if errors.Is(err, context.Canceled) {
-			return nil, err
+		retryAfter := false
+		if throttled {
+			// the server knows best when it will be able to take more requests,
+			// but a caller without a deadline must not be stalled for too long
+			if retryAfterWait, ok := parseRetryAfter(response); ok {
+				wait = min(retryAfterWait, a.maxRetryAfter)
+				retryAfter = true
+			}
 		}
 
-		// If connection timeouts or status 5xx, retry with the next node
-		if err != nil || response.StatusCode >= 500 {
-			lastResponse = response
-			lastError = err
+		closeResponseBody(lastResponse)
+		lastResponse = response
+		lastError = err
+		retryReason = describeFailedAttempt(response, err)
 
-			setNodeHealthCheck(node, UNHEALTHY)
-			time.Sleep(a.retryInterval)
-			continue
-		} else if response.StatusCode >= 1 && response.StatusCode <= 499 {
-			// Treat any status code > 0 and < 500 to be an indication that node is healthy
-			// We exclude 0 since some clients return 0 when request fails
-			setNodeHealthCheck(node, HEALTHY)
-			return response, err
+		if numTries+1 == a.numRetriesPerRequest {
+			break
+		}
+		// don't start waiting if the retry could not be made before the deadline
+		if exceedsDeadline(ctx, wait) {
+			break
+		}
+		if retryAfter {
+			a.retryAfterWaits.Add(1)
+			a.retryAfterWaitTime.Add(int64(wait))
+		}
+		a.log(ctx, slog.LevelInfo, "retrying typesense request",
+			slog.Int("attempt", attempts),
+			slog.String("node", node.url),
+			slog.String("reason", retryReason),
+			slog.Duration("wait", wait),
+			slog.Any("request", requestLogValue{req}))
+		if err := sleepContext(ctx, wait); err != nil {
+			closeResponseBody(lastResponse)
+			return nil, attempts, err
 		}
 	}
 
-	return lastResponse, lastError
+	return lastResponse, attempts, lastError
+}
+
+// ThrottlingStats returns a snapshot of the throttling responses received so far.
+func (a *APICall) ThrottlingStats() ThrottlingStats {
+	return ThrottlingStats{
+		ThrottledResponses: a.throttledResponses.Load(),
+		RetryAfterWaits:    a.retryAfterWaits.Load(),
+		RetryAfterWaitTime: time.Duration(a.retryAfterWaitTime.Load()),
+	}
 }
 
 func (a *APICall) getNextNode() *Node {
-	if a.nearestNode != nil && (a.nearestNode.isHealthy || a.nodeDueForHealthcheck(a.nearestNode)) {
+	if a.nearestNode != nil && a.nearestNode.Available() {
 		return a.nearestNode
 	}
+	return a.nodeSelector.Select(a.nodes)
+}
 
-	candidateNode := &a.nodes[0]
-	for i := 0; i <= len(a.nodes); i++ {
-		a.currentNodeIndex = (a.currentNodeIndex + 1) % len(a.nodes)
-		candidateNode = &a.nodes[a.currentNodeIndex]
-		if candidateNode.isHealthy || a.nodeDueForHealthcheck(candidateNode) {
-			return candidateNode
-		}
+// doWithNode makes a single attempt to the node and reports its latency to the node selector.
+func (a *APICall) doWithNode(node *Node, req *http.Request) (*http.Response, error) {
+	node.outstanding.Add(1)
+	defer node.outstanding.Add(-1)
+
+	start := time.Now()
+	var response *http.Response
+	var err error
+	if node.breaker == nil {
+		response, err = a.client.Do(req)
+	} else {
+		response, err = doWithNodeBreaker(node.breaker, a.client, req)
 	}
-	// None of the nodes are marked healthy, but some of them could have become healthy since last health check.
-	// So we will just return the next node.
-	return candidateNode
+	a.nodeSelector.Observe(node, time.Since(start), err)
+	return response, err
 }
 
 func (a *APICall) initializeNodesMetadata(config *ClientConfig) {
 	if config.NearestNode != "" {
-		a.nearestNode = &Node{index: "nearestNode", url: config.NearestNode}
+		a.nearestNode = &Node{index: "nearestNode", url: config.NearestNode, healthcheckInterval: a.healthcheckInterval}
 		setNodeHealthCheck(a.nearestNode, HEALTHY)
 	}
-	a.nodes = make([]Node, 0, len(config.Nodes))
+	a.nodes = make([]*Node, 0, len(config.Nodes))
 	for i, v := range config.Nodes {
-		a.nodes = append(a.nodes, Node{isHealthy: true, index: i, url: v, lastAccessTimestamp: apiCallTimeNow().UnixMilli()})
+		node := &Node{index: i, url: v, healthcheckInterval: a.healthcheckInterval}
+		setNodeHealthCheck(node, HEALTHY)
+		a.nodes = append(a.nodes, node)
+	}
+	if config.CircuitBreakerPerNode {
+		for _, node := range a.allNodes() {
+			node.breaker = newGoBreaker(config, config.CircuitBreakerName+"-"+node.url)
+		}
+	}
+}
+
+// rewindBody sets a new body on the request before every attempt. GetBody is
+// set by net/http for in-memory bodies and returns a reader over the same bytes,
+// so retrying a request does not need a copy of its body.
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+func closeResponseBody(response *http.Response) {
+	if response != nil && response.Body != nil {
+		response.Body.Close()
 	}
 }
 
@@ -158,11 +334,9 @@ func replaceRequestHostname(req *http.Request, urlToReplace string) {
 	req.Host = newURL.Host
 }
 
-func setNodeHealthCheck(node *Node, isHealthy bool) {
-	node.isHealthy = isHealthy
-	node.lastAccessTimestamp = apiCallTimeNow().UnixMilli()
-}
-
-func (a *APICall) nodeDueForHealthcheck(node *Node) bool {
-	return apiCallTimeNow().UnixMilli()-node.lastAccessTimestamp > a.healthcheckInterval.Milliseconds()
+// setNodeHealthCheck updates the health of the node and returns whether it was healthy before.
+func setNodeHealthCheck(node *Node, isHealthy bool) bool {
+	wasHealthy := node.isHealthy.Swap(isHealthy)
+	node.lastAccessTimestamp.Store(apiCallTimeNow().UnixMilli())
+	return wasHealthy
 }
@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -428,8 +430,9 @@ func TestApiCallRetryWithRequestBody(t *testing.T) {
 
 	apiCall := newAPICall(
 		&ClientConfig{
-			Nodes:             serverURLs,
-			ConnectionTimeout: 5 * time.Second,
+			Nodes:                      serverURLs,
+			ConnectionTimeout:          5 * time.Second,
+			RetryNonIdempotentRequests: true,
 		},
 	)
 	req, err := http.NewRequest(http.MethodPost, "http://example.com", bytes.NewBuffer([]byte("body data")))
@@ -445,3 +448,398 @@ func TestApiCallRetryWithRequestBody(t *testing.T) {
 
 	assert.Equal(t, serverURLs, requestURLHistory)
 }
+
+func TestApiCallIsSafeForConcurrentUse(t *testing.T) {
+	var healthyHits, unhealthyHits atomic.Int64
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			healthyHits.Add(1)
+			w.WriteHeader(200)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			unhealthyHits.Add(1)
+			w.WriteHeader(503)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			healthyHits.Add(1)
+			w.WriteHeader(200)
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:               serverURLs,
+			HealthcheckInterval: time.Minute,
+			ConnectionTimeout:   5 * time.Second,
+		},
+	)
+
+	const numGoroutines = 50
+	const requestsPerGoroutine = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < requestsPerGoroutine; j++ {
+				req := newHTTPRequest(t)
+				res, err := apiCall.Do(req)
+				if assert.NoError(t, err) {
+					assert.Equal(t, 200, res.StatusCode)
+					res.Body.Close()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(numGoroutines*requestsPerGoroutine), healthyHits.Load())
+	// the failing node is taken out of rotation after the first failures and
+	// stays out until the healthcheck interval elapses
+	assert.Less(t, unhealthyHits.Load(), int64(numGoroutines))
+}
+
+func TestApiCallRetryWaitIsAbortedWhenContextIsDone(t *testing.T) {
+	requestURLHistory := make([]string, 0, 2)
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.WriteHeader(503)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.WriteHeader(200)
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             serverURLs,
+			RetryPolicy:       &FixedIntervalRetryPolicy{Interval: time.Minute},
+			ConnectionTimeout: 5 * time.Second,
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	req := newHTTPRequest(t).WithContext(ctx)
+
+	start := time.Now()
+	res, err := apiCall.Do(req)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, res)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	assert.Equal(t, serverURLs[:1], requestURLHistory)
+}
+
+func TestApiCallDoesNotWaitPastContextDeadline(t *testing.T) {
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(503)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(200)
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             serverURLs,
+			RetryPolicy:       &FixedIntervalRetryPolicy{Interval: time.Minute},
+			ConnectionTimeout: 5 * time.Second,
+		},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req := newHTTPRequest(t).WithContext(ctx)
+
+	start := time.Now()
+	res, err := apiCall.Do(req)
+
+	// the last response is returned right away since the retry could not be
+	// made before the deadline
+	assert.NoError(t, err)
+	assert.Equal(t, 503, res.StatusCode)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestApiCallRetriesWithStatusRules(t *testing.T) {
+	requestURLHistory := make([]string, 0, 2)
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.WriteHeader(409)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.WriteHeader(201)
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             serverURLs,
+			RetryPolicy:       &FixedIntervalRetryPolicy{StatusRules: map[int]bool{409: true}},
+			ConnectionTimeout: 5 * time.Second,
+		},
+	)
+
+	res, err := apiCall.Do(newHTTPRequest(t))
+	assert.NoError(t, err)
+	assert.Equal(t, 201, res.StatusCode)
+	assert.Equal(t, serverURLs, requestURLHistory)
+}
+
+func TestApiCallRetriesThrottledRequestAfterRetryAfter(t *testing.T) {
+	requestURLHistory := make([]string, 0, 2)
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.WriteHeader(200)
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             serverURLs,
+			ConnectionTimeout: 5 * time.Second,
+		},
+	)
+
+	start := time.Now()
+	res, err := apiCall.Do(newHTTPRequest(t))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Equal(t, serverURLs, requestURLHistory)
+	assert.Equal(t, ThrottlingStats{
+		ThrottledResponses: 1,
+		RetryAfterWaits:    1,
+		RetryAfterWaitTime: time.Second,
+	}, apiCall.ThrottlingStats())
+}
+
+func TestApiCallCapsRetryAfterWithoutDeadline(t *testing.T) {
+	requestURLHistory := make([]string, 0, 2)
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.WriteHeader(200)
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             serverURLs,
+			ConnectionTimeout: 5 * time.Second,
+			MaxRetryAfter:     50 * time.Millisecond,
+		},
+	)
+
+	start := time.Now()
+	res, err := apiCall.Do(newHTTPRequest(t))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, serverURLs, requestURLHistory)
+	assert.Equal(t, ThrottlingStats{
+		ThrottledResponses: 1,
+		RetryAfterWaits:    1,
+		RetryAfterWaitTime: 50 * time.Millisecond,
+	}, apiCall.ThrottlingStats())
+}
+
+func TestApiCallRetriesServerWithoutNodes(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			ServerURL:         server.URL,
+			NumRetries:        2,
+			ConnectionTimeout: 5 * time.Second,
+			MaxRetryAfter:     50 * time.Millisecond,
+		},
+	)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/collections", nil)
+	assert.NoError(t, err)
+	res, err := apiCall.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, ThrottlingStats{
+		ThrottledResponses: 1,
+		RetryAfterWaits:    1,
+		RetryAfterWaitTime: 50 * time.Millisecond,
+	}, apiCall.ThrottlingStats())
+}
+
+func TestApiCallMakesSingleAttemptToServerWithoutNumRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	apiCall := newAPICall(&ClientConfig{ServerURL: server.URL, ConnectionTimeout: 5 * time.Second})
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/collections", nil)
+	assert.NoError(t, err)
+	res, err := apiCall.Do(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	assert.Equal(t, 1, requests)
+}
+
+func TestApiCallReturnsThrottledResponseWhenRetryAfterExceedsDeadline(t *testing.T) {
+	requestURLHistory := make([]string, 0, 1)
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.WriteHeader(200)
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             serverURLs,
+			ConnectionTimeout: 5 * time.Second,
+		},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	res, err := apiCall.Do(newHTTPRequest(t).WithContext(ctx))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, serverURLs[:1], requestURLHistory)
+	assert.Equal(t, ThrottlingStats{ThrottledResponses: 1}, apiCall.ThrottlingStats())
+}
+
+func TestApiCallDoesNotReplayNonIdempotentRequestOnServerError(t *testing.T) {
+	requestURLHistory := make([]string, 0, 1)
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.WriteHeader(500)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.WriteHeader(201)
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             serverURLs,
+			ConnectionTimeout: 5 * time.Second,
+		},
+	)
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/collections/companies/documents?action=create",
+		bytes.NewBuffer([]byte(`{"id":"123"}`)))
+	assert.NoError(t, err)
+
+	res, err := apiCall.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 500, res.StatusCode)
+	assert.Equal(t, serverURLs[:1], requestURLHistory)
+}
+
+func TestApiCallReplaysNonIdempotentRequestWhenNodeIsUnreachable(t *testing.T) {
+	requestURLHistory := make([]string, 0, 1)
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, r *http.Request) {
+			appendHistory(&requestURLHistory, r)
+			w.WriteHeader(201)
+		},
+	})
+	defer servers[0].Close()
+
+	// nothing is listening on the first node, so the request is never sent
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             []string{unreachable.URL, serverURLs[0]},
+			ConnectionTimeout: 5 * time.Second,
+		},
+	)
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/analytics/events",
+		bytes.NewBuffer([]byte(`{"name":"click"}`)))
+	assert.NoError(t, err)
+
+	res, err := apiCall.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 201, res.StatusCode)
+	assert.Equal(t, serverURLs, requestURLHistory)
+}
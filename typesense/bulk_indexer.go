@@ -0,0 +1,353 @@
+package typesense
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/typesense/typesense-go/v4/typesense/api"
+)
+
+const (
+	DefaultBulkIndexerFlushBytes    = 5 << 20
+	DefaultBulkIndexerFlushInterval = 30 * time.Second
+	DefaultBulkIndexerMaxRetries    = 5
+)
+
+// ErrBulkIndexerClosed is returned by BulkIndexer.Add once the indexer is closed.
+var ErrBulkIndexerClosed = errors.New("bulk indexer is closed")
+
+// BulkIndexerConfig configures a BulkIndexer.
+type BulkIndexerConfig struct {
+	// Collection is the name of the collection the documents are imported into.
+	Collection string
+	// NumWorkers is the number of import requests made concurrently. The
+	// requests are spread across the nodes of the client. Defaults to the
+	// number of CPUs.
+	NumWorkers int
+	// FlushDocuments and FlushBytes are the maximum number of documents and
+	// bytes of a worker's batch. A batch is imported once it is full, once
+	// FlushInterval has passed or when the indexer is closed.
+	FlushDocuments int
+	FlushBytes     int
+	FlushInterval  time.Duration
+	// Params are the parameters of the import requests.
+	Params *api.ImportDocumentsParams
+	// MaxRetries is the number of times a batch is retried when the server is
+	// overloaded, i.e. responds with 429 or 503. Defaults to
+	// DefaultBulkIndexerMaxRetries; a negative value disables retries.
+	MaxRetries int
+	// Backoff decides how long all workers pause when the server is
+	// overloaded. A Retry-After header sent by the server takes precedence, up
+	// to the MaxRetryAfter of the client.
+	// Defaults to an ExponentialBackoffRetryPolicy.
+	Backoff RetryPolicy
+	// OnFailure is called for every document that could not be imported, with
+	// the result of the document or, if the import request failed, the error.
+	// It is called concurrently by the workers.
+	OnFailure func(ctx context.Context, document any, result *api.ImportDocumentResponse, err error)
+}
+
+// BulkIndexerStats are the statistics of a BulkIndexer.
+type BulkIndexerStats struct {
+	// Added is the number of documents added to the indexer.
+	Added uint64
+	// Indexed and Failed are the numbers of documents imported successfully and unsuccessfully.
+	Indexed uint64
+	Failed  uint64
+	// Retried is the number of documents sent again because the server was overloaded.
+	Retried uint64
+	// Requests is the number of import requests made, including retries.
+	Requests uint64
+	// FlushedBytes is the size of the documents sent, including retries.
+	FlushedBytes uint64
+}
+
+// BulkIndexer imports documents in batches using concurrent workers, see
+// NewBulkIndexer. It is safe for concurrent use.
+type BulkIndexer struct {
+	config        BulkIndexerConfig
+	documents     *documents
+	maxRetryAfter time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	queue  chan bulkIndexerItem
+	wg     sync.WaitGroup
+
+	closeMu sync.RWMutex
+	closed  bool
+
+	pauseMu     sync.Mutex
+	pausedUntil time.Time
+
+	added, indexed, failed, retried, requests, flushedBytes atomic.Uint64
+}
+
+type bulkIndexerItem struct {
+	document any
+	data     []byte
+}
+
+// NewBulkIndexer returns a BulkIndexer that imports documents into
+// config.Collection and starts its workers, which run until Close is called:
+//
+//	indexer, err := typesense.NewBulkIndexer(client, typesense.BulkIndexerConfig{
+//		Collection: "companies",
+//		NumWorkers: 4,
+//		OnFailure: func(ctx context.Context, document any, result *api.ImportDocumentResponse, err error) {
+//			// log the failure
+//		},
+//	})
+//	for _, document := range documents {
+//		if err := indexer.Add(ctx, document); err != nil {
+//			return err
+//		}
+//	}
+//	err = indexer.Close(ctx)
+func NewBulkIndexer(client *Client, config BulkIndexerConfig) (*BulkIndexer, error) {
+	if config.Collection == "" {
+		return nil, errors.New("bulk indexer requires a collection")
+	}
+	if config.NumWorkers <= 0 {
+		config.NumWorkers = runtime.NumCPU()
+	}
+	if config.FlushDocuments <= 0 {
+		config.FlushDocuments = defaultImportChunkSize
+	}
+	if config.FlushBytes <= 0 {
+		config.FlushBytes = DefaultBulkIndexerFlushBytes
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultBulkIndexerFlushInterval
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	} else if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultBulkIndexerMaxRetries
+	}
+	if config.Backoff == nil {
+		config.Backoff = NewExponentialBackoffRetryPolicy()
+	}
+
+	// the workers back off together when the server is overloaded, so the
+	// client must not retry 429 and 503 responses on its own
+	ctx, cancel := context.WithCancel(contextWithoutOverloadRetries(context.Background()))
+	b := &BulkIndexer{
+		config:        config,
+		documents:     &documents{apiClient: client.apiClient, collectionName: config.Collection},
+		maxRetryAfter: client.apiConfig.MaxRetryAfter,
+		ctx:           ctx,
+		cancel:        cancel,
+		queue:         make(chan bulkIndexerItem, config.NumWorkers),
+	}
+	if b.maxRetryAfter <= 0 {
+		b.maxRetryAfter = defaultMaxRetryAfter
+	}
+	for i := 0; i < config.NumWorkers; i++ {
+		b.wg.Add(1)
+		go b.work()
+	}
+	return b, nil
+}
+
+// Add queues the document for import. It blocks while all workers are busy,
+// which applies backpressure to the caller, until ctx is done.
+func (b *BulkIndexer) Add(ctx context.Context, document any) error {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to encode document: %w", err)
+	}
+
+	b.closeMu.RLock()
+	defer b.closeMu.RUnlock()
+	if b.closed {
+		return ErrBulkIndexerClosed
+	}
+	select {
+	case b.queue <- bulkIndexerItem{document: document, data: data}:
+		b.added.Add(1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close imports the remaining documents and stops the workers. If ctx is done
+// first, the pending imports are cancelled and the error of ctx is returned.
+func (b *BulkIndexer) Close(ctx context.Context) error {
+	b.closeMu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.closeMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Stats returns a snapshot of the statistics of the indexer.
+func (b *BulkIndexer) Stats() BulkIndexerStats {
+	return BulkIndexerStats{
+		Added:        b.added.Load(),
+		Indexed:      b.indexed.Load(),
+		Failed:       b.failed.Load(),
+		Retried:      b.retried.Load(),
+		Requests:     b.requests.Load(),
+		FlushedBytes: b.flushedBytes.Load(),
+	}
+}
+
+// bulkIndexerBatch is the batch of documents of a worker.
+type bulkIndexerBatch struct {
+	items []bulkIndexerItem
+	body  bytes.Buffer
+}
+
+func (b *BulkIndexer) work() {
+	defer b.wg.Done()
+	batch := &bulkIndexerBatch{}
+	ticker := time.NewTicker(b.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case item, ok := <-b.queue:
+			if !ok {
+				b.flush(batch)
+				return
+			}
+			if len(batch.items) > 0 && batch.body.Len()+len(item.data)+1 > b.config.FlushBytes {
+				batch = b.flush(batch)
+			}
+			batch.items = append(batch.items, item)
+			batch.body.Write(item.data)
+			batch.body.WriteByte('\n')
+			if len(batch.items) >= b.config.FlushDocuments {
+				batch = b.flush(batch)
+			}
+		case <-ticker.C:
+			if len(batch.items) > 0 {
+				batch = b.flush(batch)
+			}
+		}
+	}
+}
+
+// flush imports the batch, retrying it while the server is overloaded, and
+// returns a new empty batch.
+func (b *BulkIndexer) flush(batch *bulkIndexerBatch) *bulkIndexerBatch {
+	if len(batch.items) == 0 {
+		return batch
+	}
+	start := time.Now()
+	body := batch.body.Bytes()
+	for attempt := 1; ; attempt++ {
+		if err := b.waitWhilePaused(); err != nil {
+			b.fail(batch.items, err)
+			break
+		}
+		b.requests.Add(1)
+		b.flushedBytes.Add(uint64(len(body)))
+		err := b.importBatch(batch.items, body)
+		if err == nil {
+			break
+		}
+
+		var httpErr *HTTPError
+		overloaded := errors.As(err, &httpErr) &&
+			(httpErr.Status == http.StatusTooManyRequests || httpErr.Status == http.StatusServiceUnavailable)
+		if !overloaded || attempt > b.config.MaxRetries {
+			b.fail(batch.items, err)
+			break
+		}
+		wait, retry := b.config.Backoff.NextRetry(&RetryAttempt{Attempt: attempt, Elapsed: time.Since(start), Err: err})
+		if !retry {
+			b.fail(batch.items, err)
+			break
+		}
+		b.pause(max(wait, min(httpErr.RetryAfter, b.maxRetryAfter)))
+		b.retried.Add(uint64(len(batch.items)))
+	}
+	return &bulkIndexerBatch{}
+}
+
+// importBatch imports the documents and reports the failed ones. It returns
+// an error only if the request failed, in which case it can be retried.
+func (b *BulkIndexer) importBatch(items []bulkIndexerItem, body []byte) error {
+	params := &api.ImportDocumentsParams{}
+	if b.config.Params != nil {
+		*params = *b.config.Params
+	}
+	response, err := b.documents.ImportJsonl(b.ctx, bytes.NewReader(body), params)
+	if err != nil {
+		return err
+	}
+	defer response.Close()
+
+	decoder := json.NewDecoder(response)
+	for i, item := range items {
+		var result *api.ImportDocumentResponse
+		if err := decoder.Decode(&result); err != nil {
+			// the documents may have been imported, so they are not retried
+			b.fail(items[i:], fmt.Errorf("failed to decode result: %w", err))
+			return nil
+		}
+		if result.Success {
+			b.indexed.Add(1)
+			continue
+		}
+		b.failed.Add(1)
+		if b.config.OnFailure != nil {
+			b.config.OnFailure(b.ctx, item.document, result, nil)
+		}
+	}
+	return nil
+}
+
+func (b *BulkIndexer) fail(items []bulkIndexerItem, err error) {
+	b.failed.Add(uint64(len(items)))
+	if b.config.OnFailure == nil {
+		return
+	}
+	for _, item := range items {
+		b.config.OnFailure(b.ctx, item.document, nil, err)
+	}
+}
+
+// pause makes all workers wait for d before their next import request, so that
+// an overloaded server is given time to recover.
+func (b *BulkIndexer) pause(d time.Duration) {
+	b.pauseMu.Lock()
+	defer b.pauseMu.Unlock()
+	if until := time.Now().Add(d); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+func (b *BulkIndexer) waitWhilePaused() error {
+	b.pauseMu.Lock()
+	wait := time.Until(b.pausedUntil)
+	b.pauseMu.Unlock()
+	return sleepContext(b.ctx, wait)
+}
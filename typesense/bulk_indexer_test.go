@@ -0,0 +1,291 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+)
+
+// respondToBulkImport answers an import request with a success for every
+// document, except for documents without a company name.
+func respondToBulkImport(w http.ResponseWriter, _ *http.Request, _ int, lines []string) {
+	for _, line := range lines {
+		if strings.Contains(line, `"company_name":""`) {
+			fmt.Fprintln(w, `{"success": false, "error": "Field company_name must not be empty."}`)
+			continue
+		}
+		fmt.Fprintln(w, `{"success": true}`)
+	}
+}
+
+func batchSizes(requests [][]string) []int {
+	var sizes []int
+	for _, lines := range requests {
+		sizes = append(sizes, len(lines))
+	}
+	return sizes
+}
+
+func TestBulkIndexerImportsDocumentsInBatches(t *testing.T) {
+	client, requests := newRecordingTestClient(t, importedLines(t), respondToBulkImport)
+
+	var failures []any
+	indexer, err := NewBulkIndexer(client, BulkIndexerConfig{
+		Collection:     "companies",
+		NumWorkers:     1,
+		FlushDocuments: 10,
+		OnFailure: func(_ context.Context, document any, result *api.ImportDocumentResponse, err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, "Field company_name must not be empty.", result.Error)
+			failures = append(failures, document)
+		},
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 25; i++ {
+		document := companyDocument{ID: fmt.Sprint(i), CompanyName: "Stark Industries", NumEmployees: i}
+		if i == 12 {
+			document.CompanyName = ""
+		}
+		assert.NoError(t, indexer.Add(context.Background(), document))
+	}
+	assert.NoError(t, indexer.Close(context.Background()))
+
+	assert.Equal(t, []int{10, 10, 5}, batchSizes(requests()))
+	assert.Equal(t, []any{companyDocument{ID: "12", NumEmployees: 12}}, failures)
+	stats := indexer.Stats()
+	assert.Equal(t, uint64(25), stats.Added)
+	assert.Equal(t, uint64(24), stats.Indexed)
+	assert.Equal(t, uint64(1), stats.Failed)
+	assert.Equal(t, uint64(0), stats.Retried)
+	assert.Equal(t, uint64(3), stats.Requests)
+	assert.Greater(t, stats.FlushedBytes, uint64(25*50))
+
+	assert.ErrorIs(t, indexer.Add(context.Background(), companyDocument{}), ErrBulkIndexerClosed)
+}
+
+func TestBulkIndexerFlushBytes(t *testing.T) {
+	client, requests := newRecordingTestClient(t, importedLines(t), respondToBulkImport)
+
+	// every document is 62 bytes long including the newline
+	indexer, err := NewBulkIndexer(client, BulkIndexerConfig{Collection: "companies", NumWorkers: 1, FlushBytes: 130})
+	assert.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, indexer.Add(context.Background(), companyDocument{ID: fmt.Sprint(i), CompanyName: "Stark Industries", NumEmployees: 1}))
+	}
+	assert.NoError(t, indexer.Close(context.Background()))
+
+	assert.Equal(t, []int{2, 2, 1}, batchSizes(requests()))
+}
+
+func TestBulkIndexerFlushesOnInterval(t *testing.T) {
+	client, _ := newRecordingTestClient(t, importedLines(t), respondToBulkImport)
+
+	indexer, err := NewBulkIndexer(client, BulkIndexerConfig{Collection: "companies", NumWorkers: 1, FlushInterval: 10 * time.Millisecond})
+	assert.NoError(t, err)
+	defer indexer.Close(context.Background())
+
+	assert.NoError(t, indexer.Add(context.Background(), companyDocument{ID: "1", CompanyName: "Stark Industries"}))
+	assert.Eventually(t, func() bool {
+		return indexer.Stats().Indexed == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestBulkIndexerRunsWorkersConcurrently(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int64
+	client, _ := newRecordingTestClient(t, importedLines(t), func(w http.ResponseWriter, r *http.Request, request int, lines []string) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		respondToBulkImport(w, r, request, lines)
+	})
+
+	indexer, err := NewBulkIndexer(client, BulkIndexerConfig{Collection: "companies", NumWorkers: 4, FlushDocuments: 1})
+	assert.NoError(t, err)
+	for i := 0; i < 16; i++ {
+		assert.NoError(t, indexer.Add(context.Background(), companyDocument{ID: fmt.Sprint(i), CompanyName: "Stark Industries"}))
+	}
+	assert.NoError(t, indexer.Close(context.Background()))
+
+	assert.Equal(t, uint64(16), indexer.Stats().Indexed)
+	assert.Greater(t, maxInFlight.Load(), int64(1))
+	assert.LessOrEqual(t, maxInFlight.Load(), int64(4))
+}
+
+func TestBulkIndexerRetriesWhenServerIsOverloaded(t *testing.T) {
+	client, _ := newRecordingTestClient(t, importedLines(t), func(w http.ResponseWriter, r *http.Request, request int, lines []string) {
+		switch request {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			respondToBulkImport(w, r, request, lines)
+		}
+	})
+
+	indexer, err := NewBulkIndexer(client, BulkIndexerConfig{
+		Collection: "companies",
+		NumWorkers: 1,
+		Backoff:    &FixedIntervalRetryPolicy{Interval: time.Millisecond},
+	})
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, indexer.Add(context.Background(), companyDocument{ID: fmt.Sprint(i), CompanyName: "Stark Industries"}))
+	}
+	assert.NoError(t, indexer.Close(context.Background()))
+
+	stats := indexer.Stats()
+	assert.Equal(t, uint64(3), stats.Indexed)
+	assert.Equal(t, uint64(0), stats.Failed)
+	assert.Equal(t, uint64(6), stats.Retried)
+	assert.Equal(t, uint64(3), stats.Requests)
+}
+
+func TestBulkIndexerHonorsRetryAfter(t *testing.T) {
+	client, _ := newRecordingTestClient(t, importedLines(t), func(w http.ResponseWriter, r *http.Request, request int, lines []string) {
+		if request == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		respondToBulkImport(w, r, request, lines)
+	})
+
+	indexer, err := NewBulkIndexer(client, BulkIndexerConfig{
+		Collection: "companies",
+		NumWorkers: 1,
+		Backoff:    &FixedIntervalRetryPolicy{Interval: time.Millisecond},
+	})
+	assert.NoError(t, err)
+	start := time.Now()
+	assert.NoError(t, indexer.Add(context.Background(), companyDocument{ID: "1", CompanyName: "Stark Industries"}))
+	assert.NoError(t, indexer.Close(context.Background()))
+
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Equal(t, uint64(1), indexer.Stats().Indexed)
+}
+
+func TestBulkIndexerPausesAllWorkersOnFirstThrottledResponse(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := int(requests.Add(1))
+		if request == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		respondToBulkImport(w, r, request, importedLines(t)(r))
+	}))
+	defer server.Close()
+
+	// the client would retry the throttled request right away on its own
+	client := NewClient(WithNodes([]string{server.URL}), WithNumRetries(5), WithRetryInterval(0))
+	defer client.Close()
+	indexer, err := NewBulkIndexer(client, BulkIndexerConfig{
+		Collection:     "companies",
+		NumWorkers:     4,
+		FlushDocuments: 1,
+		Backoff:        &FixedIntervalRetryPolicy{Interval: 300 * time.Millisecond},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, indexer.Add(context.Background(), companyDocument{ID: "0", CompanyName: "Stark Industries"}))
+	assert.Eventually(t, func() bool {
+		return indexer.Stats().Retried == 1
+	}, time.Second, time.Millisecond)
+	for i := 1; i < 4; i++ {
+		assert.NoError(t, indexer.Add(context.Background(), companyDocument{ID: fmt.Sprint(i), CompanyName: "Stark Industries"}))
+	}
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int64(1), requests.Load())
+
+	assert.NoError(t, indexer.Close(context.Background()))
+	assert.Equal(t, int64(5), requests.Load())
+	assert.Equal(t, uint64(4), indexer.Stats().Indexed)
+}
+
+func TestBulkIndexerReportsFailedRequests(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	var failures atomic.Int64
+	indexer, err := NewBulkIndexer(client, BulkIndexerConfig{
+		Collection: "companies",
+		NumWorkers: 1,
+		MaxRetries: 2,
+		Backoff:    &FixedIntervalRetryPolicy{Interval: time.Millisecond},
+		OnFailure: func(_ context.Context, _ any, result *api.ImportDocumentResponse, err error) {
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, ErrServerUnavailable)
+			failures.Add(1)
+		},
+	})
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, indexer.Add(context.Background(), companyDocument{ID: fmt.Sprint(i)}))
+	}
+	assert.NoError(t, indexer.Close(context.Background()))
+
+	assert.Equal(t, int64(2), failures.Load())
+	stats := indexer.Stats()
+	assert.Equal(t, uint64(2), stats.Failed)
+	assert.Equal(t, uint64(4), stats.Retried)
+	assert.Equal(t, uint64(3), stats.Requests)
+}
+
+func TestBulkIndexerDoesNotRetryBadRequests(t *testing.T) {
+	var requests atomic.Int64
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	defer server.Close()
+
+	indexer, err := NewBulkIndexer(client, BulkIndexerConfig{Collection: "companies", NumWorkers: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, indexer.Add(context.Background(), companyDocument{ID: "1"}))
+	assert.NoError(t, indexer.Close(context.Background()))
+
+	assert.Equal(t, int64(1), requests.Load())
+	assert.Equal(t, uint64(1), indexer.Stats().Failed)
+}
+
+func TestBulkIndexerCloseCancelsImportsWhenContextIsDone(t *testing.T) {
+	server, client := newTestServerAndClient(func(_ http.ResponseWriter, r *http.Request) {
+		// the server notices that the client went away once the body is read
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	})
+	defer server.Close()
+
+	indexer, err := NewBulkIndexer(client, BulkIndexerConfig{Collection: "companies", NumWorkers: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, indexer.Add(context.Background(), companyDocument{ID: "1"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, indexer.Close(ctx), context.DeadlineExceeded)
+	assert.Equal(t, uint64(1), indexer.Stats().Failed)
+}
+
+func TestNewBulkIndexerRequiresCollection(t *testing.T) {
+	_, err := NewBulkIndexer(NewClient(WithServer("http://localhost:8108")), BulkIndexerConfig{})
+	assert.Error(t, err)
+}
@@ -4,7 +4,7 @@ package typesense
 
 import (
 	"context"
-	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -20,18 +20,20 @@ type APIClientInterface interface {
 type Client struct {
 	apiConfig    *ClientConfig
 	apiClient    APIClientInterface
-	collections  CollectionsInterface
+	apiCall      *APICall
+	breaker      circuit.Breaker
+	collections  CollectionsPlannerInterface
 	aliases      AliasesInterface
 	MultiSearch  MultiSearchInterface
 	synonymSets  SynonymSetsInterface
 	curationSets CurationSetsInterface
 }
 
-func (c *Client) Collections() CollectionsInterface {
+func (c *Client) Collections() CollectionsPlannerInterface {
 	return c.collections
 }
 
-func GenericCollection[T any](c *Client, collectionName string) CollectionInterface[T] {
+func GenericCollection[T any](c *Client, collectionName string) GenericCollectionInterface[T] {
 	return &collection[T]{apiClient: c.apiClient, name: collectionName}
 }
 
@@ -115,28 +117,57 @@ func (c *Client) Stats() StatsInterface {
 	return &stats{apiClient: c.apiClient}
 }
 
-func (c *Client) Metrics() MetricsInterface {
+func (c *Client) Metrics() ServerMetricsInterface {
 	return &metrics{apiClient: c.apiClient}
 }
 
-// Debug retrieves debug information from the Typesense server
-func (c *Client) Debug(ctx context.Context) (*api.DebugResponse, error) {
-	return c.apiClient.DebugWithResponse(ctx)
+// ThrottlingStats returns how often the server asked the client to slow down
+// with 429 or 503 responses and how long the client waited because of it.
+// It returns zero values if the client was created WithAPIClient.
+func (c *Client) ThrottlingStats() ThrottlingStats {
+	if c.apiCall == nil {
+		return ThrottlingStats{}
+	}
+	return c.apiCall.ThrottlingStats()
 }
 
-type HTTPError struct {
-	Status int
-	Body   []byte
+// NodeStatus returns a snapshot of the health of the configured nodes, including the
+// outcome of the last background probe when WithHealthProbeInterval is enabled.
+// It returns nil if the client was created WithAPIClient.
+func (c *Client) NodeStatus() []NodeStatus {
+	if c.apiCall == nil {
+		return nil
+	}
+	return c.apiCall.NodeStatus()
 }
 
-func (e *HTTPError) Error() string {
-	return fmt.Sprintf("status: %v response: %s", e.Status, string(e.Body))
+// CircuitBreakerState returns the state of the circuit breaker wrapping all requests,
+// e.g. "open", or an empty string if the client has none or its state is unknown.
+func (c *Client) CircuitBreakerState() string {
+	if state, ok := breakerState(c.breaker); ok {
+		return state.String()
+	}
+	return ""
+}
+
+// Close releases the resources of the client, such as the background health checks.
+func (c *Client) Close() error {
+	if c.apiCall != nil {
+		c.apiCall.Close()
+	}
+	return nil
+}
+
+// Debug retrieves debug information from the Typesense server
+func (c *Client) Debug(ctx context.Context) (*api.DebugResponse, error) {
+	return c.apiClient.DebugWithResponse(ctx)
 }
 
 const (
 	defaultRetryInterval       = 100 * time.Millisecond
 	defaultHealthcheckInterval = 1 * time.Minute
 	defaultConnectionTimeout   = 5 * time.Second
+	defaultMaxRetryAfter       = 30 * time.Second
 	defaultCircuitBreakerName  = "typesenseClient"
 )
 
@@ -144,9 +175,16 @@ type ClientConfig struct {
 	ServerURL                   string
 	NearestNode                 string // optional
 	Nodes                       []string
+	NodeSelector                NodeSelector // optional
 	NumRetries                  int
 	RetryInterval               time.Duration
+	RetryPolicy                 RetryPolicy   // optional, overrides RetryInterval
+	MaxRetryAfter               time.Duration // optional
+	RetryNonIdempotentRequests  bool
+	IdempotencyClassifier       IdempotencyClassifier // optional
 	HealthcheckInterval         time.Duration
+	HealthProbeInterval         time.Duration // optional
+	HedgeDelay                  time.Duration // optional
 	APIKey                      string
 	ConnectionTimeout           time.Duration
 	CircuitBreakerName          string
@@ -155,7 +193,12 @@ type ClientConfig struct {
 	CircuitBreakerTimeout       time.Duration
 	CircuitBreakerReadyToTrip   circuit.GoBreakerReadyToTripFunc
 	CircuitBreakerOnStateChange circuit.GoBreakerOnStateChangeFunc
+	CircuitBreakerPerNode       bool
+	CircuitBreaker              circuit.Breaker // optional, replaces the client-wide GoBreaker
 	CustomHTTPClient            *http.Client
+	Middlewares                 []Middleware
+	AttemptMiddlewares          []Middleware
+	Logger                      *slog.Logger
 }
 
 type ClientOption func(*Client)
@@ -188,8 +231,20 @@ func WithNodes(nodeURLs []string) ClientOption {
 	}
 }
 
+// WithNodeSelector sets the strategy that picks the node each request is sent to.
+// The nearest node, if set, is still preferred as long as it is healthy.
+// Built-in strategies are NewRoundRobinSelector, NewLeastOutstandingSelector
+// and NewPeakEWMASelector.
+// Default is round-robin.
+func WithNodeSelector(selector NodeSelector) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.NodeSelector = selector
+	}
+}
+
 // WithNumRetries sets the number of retries per request.
-// Default value is the number of nodes (+1 if nearestNode is specified).
+// Default value is the number of nodes (+1 if nearestNode is specified). A client
+// without nodes makes a single attempt to its server unless this is set.
 func WithNumRetries(num int) ClientOption {
 	return func(c *Client) {
 		c.apiConfig.NumRetries = num
@@ -204,6 +259,47 @@ func WithRetryInterval(duration time.Duration) ClientOption {
 	}
 }
 
+// WithRetryPolicy sets the policy that decides whether a failed request is retried
+// on the next node and how long to wait before doing so. Waiting is aborted as soon
+// as the request context is done.
+// Default is a FixedIntervalRetryPolicy using the configured RetryInterval.
+// A client without nodes only retries requests to its server if WithNumRetries is set.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.RetryPolicy = policy
+	}
+}
+
+// WithMaxRetryAfter sets the maximum time to wait before a retry when a
+// throttled response asks to retry after a longer time with a Retry-After header.
+// Default value is 30 seconds.
+func WithMaxRetryAfter(duration time.Duration) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.MaxRetryAfter = duration
+	}
+}
+
+// WithRetryNonIdempotentRequests sets whether requests that are not idempotent, e.g.
+// creating documents, are replayed on the next node after a 5xx response or a timeout.
+// Such requests may already have been applied by the failing node, so replaying
+// them can create duplicates. Requests that were never processed by the server
+// (connection refused, 429 or 503 with Retry-After) are always retried.
+// Default value is false.
+func WithRetryNonIdempotentRequests(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.RetryNonIdempotentRequests = enabled
+	}
+}
+
+// WithIdempotencyClassifier sets the function that decides which requests are
+// idempotent and therefore safe to replay on the next node.
+// Default is DefaultIdempotencyClassifier.
+func WithIdempotencyClassifier(classifier IdempotencyClassifier) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.IdempotencyClassifier = classifier
+	}
+}
+
 // WithHealthcheckInterval sets the wait time for an unhealthy node to become healthy again.
 // A node is marked as unhealthy if its response status code is 5xx or has an error (e.g. timeout).
 // Default value is 1 minute.
@@ -213,6 +309,29 @@ func WithHealthcheckInterval(duration time.Duration) ClientOption {
 	}
 }
 
+// WithHealthProbeInterval enables a background prober that calls the /health endpoint
+// of the nearest node and every other node once per interval, so that nodes are marked
+// healthy or unhealthy without waiting for a request to fail or the HealthcheckInterval
+// to elapse. The prober runs until Client.Close is called.
+// It is disabled by default.
+func WithHealthProbeInterval(interval time.Duration) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.HealthProbeInterval = interval
+	}
+}
+
+// WithHedgeDelay enables hedging of searches (Documents().Search and MultiSearch):
+// if no response has arrived after the delay, the same search is sent to another
+// healthy node and the first successful response is used, while the other request
+// is cancelled. Use ContextWithHedgeDelay to override the delay per call.
+// Hedging only applies when there is more than one node.
+// It is disabled by default.
+func WithHedgeDelay(delay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.HedgeDelay = delay
+	}
+}
+
 // WithAPIKey sets the API token.
 func WithAPIKey(apiKey string) ClientOption {
 	return func(c *Client) {
@@ -285,15 +404,43 @@ func WithCircuitBreakerOnStateChange(onStateChange circuit.GoBreakerOnStateChang
 	}
 }
 
+// WithCircuitBreaker sets the CircuitBreaker for the whole client, replacing the GoBreaker
+// built from the CircuitBreaker settings above. Use circuit.NoopBreaker to disable circuit
+// breaking entirely. It is used in addition to the breakers enabled with WithCircuitBreakerPerNode.
+func WithCircuitBreaker(breaker circuit.Breaker) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.CircuitBreaker = breaker
+	}
+}
+
+// WithCircuitBreakerPerNode sets whether every node gets its own CircuitBreaker instead
+// of one CircuitBreaker for the whole client. A node whose CircuitBreaker is open is
+// skipped, while the other nodes keep serving requests. Transport errors and 5xx
+// responses count as failures. The CircuitBreaker settings above apply to every node,
+// the name of each CircuitBreaker is suffixed with the node URL.
+// It only applies when Nodes or NearestNode are set. Default value is false.
+func WithCircuitBreakerPerNode(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.CircuitBreakerPerNode = enabled
+	}
+}
+
 // WithClientConfig allows to pass all configs at once
 func WithClientConfig(config *ClientConfig) ClientOption {
 	return func(c *Client) {
 		c.apiConfig.ServerURL = config.ServerURL
 		c.apiConfig.NearestNode = config.NearestNode
 		c.apiConfig.Nodes = config.Nodes
+		c.apiConfig.NodeSelector = config.NodeSelector
 		c.apiConfig.NumRetries = config.NumRetries
 		c.apiConfig.RetryInterval = config.RetryInterval
+		c.apiConfig.RetryPolicy = config.RetryPolicy
+		c.apiConfig.MaxRetryAfter = config.MaxRetryAfter
+		c.apiConfig.RetryNonIdempotentRequests = config.RetryNonIdempotentRequests
+		c.apiConfig.IdempotencyClassifier = config.IdempotencyClassifier
 		c.apiConfig.HealthcheckInterval = config.HealthcheckInterval
+		c.apiConfig.HealthProbeInterval = config.HealthProbeInterval
+		c.apiConfig.HedgeDelay = config.HedgeDelay
 		c.apiConfig.APIKey = config.APIKey
 		c.apiConfig.ConnectionTimeout = config.ConnectionTimeout
 		c.apiConfig.CircuitBreakerName = config.CircuitBreakerName
@@ -302,6 +449,41 @@ func WithClientConfig(config *ClientConfig) ClientOption {
 		c.apiConfig.CircuitBreakerTimeout = config.CircuitBreakerTimeout
 		c.apiConfig.CircuitBreakerReadyToTrip = config.CircuitBreakerReadyToTrip
 		c.apiConfig.CircuitBreakerOnStateChange = config.CircuitBreakerOnStateChange
+		c.apiConfig.CircuitBreakerPerNode = config.CircuitBreakerPerNode
+		c.apiConfig.CircuitBreaker = config.CircuitBreaker
+		c.apiConfig.Middlewares = config.Middlewares
+		c.apiConfig.AttemptMiddlewares = config.AttemptMiddlewares
+		c.apiConfig.Logger = config.Logger
+	}
+}
+
+// WithLogger sets the logger the client reports node health changes, retries,
+// failed requests and circuit breaker state changes to. The API key is redacted
+// from the logged requests. By default, nothing is logged.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.Logger = logger
+	}
+}
+
+// WithMiddleware adds middlewares that see every logical call made by the client once,
+// before the circuit breaker, node selection and retries. Middlewares run in the order
+// they are added, the first one being the first to see a request.
+// They are not used if the client is created WithAPIClient.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.Middlewares = append(c.apiConfig.Middlewares, middlewares...)
+	}
+}
+
+// WithAttemptMiddleware adds middlewares that see every attempt sent to a node,
+// including retries on other nodes, hedged searches and background health probes.
+// Middlewares run in the order they are added, the first one being the first
+// to see a request.
+// They are not used if the client is created WithAPIClient.
+func WithAttemptMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.apiConfig.AttemptMiddlewares = append(c.apiConfig.AttemptMiddlewares, middlewares...)
 	}
 }
 
@@ -327,28 +509,26 @@ func NewClient(opts ...ClientOption) *Client {
 		opt(c)
 	}
 	if c.apiClient == nil {
-		cb := circuit.NewGoBreaker(
-			circuit.WithGoBreakerName(c.apiConfig.CircuitBreakerName),
-			circuit.WithGoBreakerMaxRequests(c.apiConfig.CircuitBreakerMaxRequests),
-			circuit.WithGoBreakerInterval(c.apiConfig.CircuitBreakerInterval),
-			circuit.WithGoBreakerTimeout(c.apiConfig.CircuitBreakerTimeout),
-			circuit.WithGoBreakerReadyToTrip(c.apiConfig.CircuitBreakerReadyToTrip),
-			circuit.WithGoBreakerOnStateChange(c.apiConfig.CircuitBreakerOnStateChange),
-		)
 		client := c.apiConfig.CustomHTTPClient
 		if client == nil {
 			client = &http.Client{
 				Timeout: c.apiConfig.ConnectionTimeout,
 			}
 		}
-		httpClient := circuit.NewHTTPClient(
-			circuit.WithHTTPRequestDoer(
-				NewAPICall(
-					client,
-					c.apiConfig,
-				)),
-			circuit.WithCircuitBreaker(cb),
-		)
+		c.apiCall = NewAPICall(chainMiddlewares(client, c.apiConfig.AttemptMiddlewares), c.apiConfig)
+		var httpClient circuit.HTTPRequestDoer = c.apiCall
+		switch {
+		case c.apiConfig.CircuitBreaker != nil:
+			c.breaker = c.apiConfig.CircuitBreaker
+		case !c.apiConfig.CircuitBreakerPerNode || len(c.apiCall.allNodes()) == 0:
+			c.breaker = newGoBreaker(c.apiConfig, c.apiConfig.CircuitBreakerName)
+		}
+		if c.breaker != nil {
+			httpClient = circuit.NewHTTPClient(
+				circuit.WithHTTPRequestDoer(c.apiCall),
+				circuit.WithCircuitBreaker(c.breaker),
+			)
+		}
 		serverURL := ""
 
 		switch {
@@ -364,7 +544,7 @@ func NewClient(opts ...ClientOption) *Client {
 
 		apiClient, _ := api.NewClientWithResponses(serverURL,
 			api.WithAPIKey(c.apiConfig.APIKey),
-			api.WithHTTPClient(httpClient))
+			api.WithHTTPClient(chainMiddlewares(httpClient, c.apiConfig.Middlewares)))
 		c.apiClient = apiClient
 	}
 	c.collections = &collections{c.apiClient}
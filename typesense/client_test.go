@@ -1,6 +1,9 @@
 package typesense
 
 import (
+	"context"
+	"io"
+	"log/slog"
 	"net/http"
 	"reflect"
 	"testing"
@@ -33,6 +36,7 @@ func TestClientConfigOptions(t *testing.T) {
 			(float64(counts.TotalFailures)/float64(counts.Requests)) > 0.4
 	}
 	onStateChange := func(_ string, _ gobreaker.State, _ gobreaker.State) {}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	tests := []struct {
 		name    string
 		options []ClientOption
@@ -89,6 +93,16 @@ func TestClientConfigOptions(t *testing.T) {
 				assert.Equal(t, "http://localhost:3000/", apiClient.Server)
 			},
 		},
+		{
+			name: "WithNodeSelector",
+			options: []ClientOption{
+				WithNodeSelector(NewLeastOutstandingSelector()),
+			},
+			verify: func(t *testing.T, client *Client) {
+				assert.IsType(t, &LeastOutstandingSelector{}, client.apiConfig.NodeSelector)
+				assert.NotNil(t, client.apiClient)
+			},
+		},
 		{
 			name: "WithNumRetries",
 			options: []ClientOption{
@@ -109,6 +123,36 @@ func TestClientConfigOptions(t *testing.T) {
 				assert.NotNil(t, client.apiClient)
 			},
 		},
+		{
+			name: "WithMaxRetryAfter",
+			options: []ClientOption{
+				WithMaxRetryAfter(10 * time.Second),
+			},
+			verify: func(t *testing.T, client *Client) {
+				assert.Equal(t, 10*time.Second, client.apiConfig.MaxRetryAfter)
+				assert.NotNil(t, client.apiClient)
+			},
+		},
+		{
+			name: "WithRetryPolicy",
+			options: []ClientOption{
+				WithRetryPolicy(NewExponentialBackoffRetryPolicy()),
+			},
+			verify: func(t *testing.T, client *Client) {
+				assert.Equal(t, NewExponentialBackoffRetryPolicy(), client.apiConfig.RetryPolicy)
+				assert.NotNil(t, client.apiClient)
+			},
+		},
+		{
+			name: "WithRetryNonIdempotentRequests",
+			options: []ClientOption{
+				WithRetryNonIdempotentRequests(true),
+			},
+			verify: func(t *testing.T, client *Client) {
+				assert.True(t, client.apiConfig.RetryNonIdempotentRequests)
+				assert.NotNil(t, client.apiClient)
+			},
+		},
 		{
 			name: "WithHealthcheckInterval",
 			options: []ClientOption{
@@ -119,6 +163,16 @@ func TestClientConfigOptions(t *testing.T) {
 				assert.NotNil(t, client.apiClient)
 			},
 		},
+		{
+			name: "WithHedgeDelay",
+			options: []ClientOption{
+				WithHedgeDelay(50 * time.Millisecond),
+			},
+			verify: func(t *testing.T, client *Client) {
+				assert.Equal(t, 50*time.Millisecond, client.apiConfig.HedgeDelay)
+				assert.NotNil(t, client.apiClient)
+			},
+		},
 		{
 			name: "WithAPIKey",
 			options: []ClientOption{
@@ -205,6 +259,39 @@ func TestClientConfigOptions(t *testing.T) {
 				assert.NotNil(t, client.apiClient)
 			},
 		},
+		{
+			name: "WithCircuitBreaker",
+			options: []ClientOption{
+				WithCircuitBreaker(circuit.NoopBreaker{}),
+			},
+			verify: func(t *testing.T, client *Client) {
+				assert.Equal(t, circuit.NoopBreaker{}, client.apiConfig.CircuitBreaker)
+				assert.NotNil(t, client.apiClient)
+			},
+		},
+		{
+			name: "WithLogger",
+			options: []ClientOption{
+				WithLogger(logger),
+			},
+			verify: func(t *testing.T, client *Client) {
+				assert.Equal(t, logger, client.apiConfig.Logger)
+				assert.NotNil(t, client.apiClient)
+			},
+		},
+		{
+			name: "WithMiddleware",
+			options: []ClientOption{
+				WithMiddleware(passthroughMiddleware, passthroughMiddleware),
+				WithMiddleware(passthroughMiddleware),
+				WithAttemptMiddleware(passthroughMiddleware),
+			},
+			verify: func(t *testing.T, client *Client) {
+				assert.Len(t, client.apiConfig.Middlewares, 3)
+				assert.Len(t, client.apiConfig.AttemptMiddlewares, 1)
+				assert.NotNil(t, client.apiClient)
+			},
+		},
 		{
 			name: "WithConfig",
 			options: []ClientOption{
@@ -212,6 +299,7 @@ func TestClientConfigOptions(t *testing.T) {
 					ServerURL:                   "http://example.com",
 					APIKey:                      "API_KEY_1",
 					ConnectionTimeout:           5 * time.Second,
+					MaxRetryAfter:               20 * time.Second,
 					CircuitBreakerName:          "typesense_2",
 					CircuitBreakerMaxRequests:   100,
 					CircuitBreakerInterval:      30 * time.Second,
@@ -224,6 +312,8 @@ func TestClientConfigOptions(t *testing.T) {
 				assert.Equal(t, "http://example.com", client.apiConfig.ServerURL)
 				assert.Equal(t, "API_KEY_1", client.apiConfig.APIKey)
 				assert.Equal(t, 5*time.Second, client.apiConfig.ConnectionTimeout)
+				assert.Equal(t, 20*time.Second, client.apiConfig.MaxRetryAfter)
+				assert.Equal(t, 20*time.Second, client.apiCall.maxRetryAfter)
 				assert.Equal(t, "typesense_2", client.apiConfig.CircuitBreakerName)
 				assert.Equal(t, uint32(100), client.apiConfig.CircuitBreakerMaxRequests)
 				assert.Equal(t, 30*time.Second, client.apiConfig.CircuitBreakerInterval)
@@ -260,3 +350,61 @@ func TestClientConfigOptions(t *testing.T) {
 		})
 	}
 }
+
+type countingBreaker struct {
+	executions int
+}
+
+func (b *countingBreaker) Execute(req func() error) error {
+	b.executions++
+	return req()
+}
+
+func TestClientWithCustomCircuitBreaker(t *testing.T) {
+	server, _ := newTestServerAndClient(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	})
+	defer server.Close()
+
+	breaker := &countingBreaker{}
+	client := NewClient(WithServer(server.URL), WithCircuitBreaker(breaker))
+
+	healthy, err := client.Health(context.Background(), time.Second)
+	assert.NoError(t, err)
+	assert.True(t, healthy)
+	assert.Equal(t, 1, breaker.executions)
+}
+
+func TestClientWithNoopBreakerNeverTrips(t *testing.T) {
+	server, _ := newTestServerAndClient(func(w http.ResponseWriter, _ *http.Request) {
+		// hijack the connection to make every request fail with a transport error
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	})
+	defer server.Close()
+
+	client := NewClient(
+		WithServer(server.URL),
+		WithCircuitBreaker(circuit.NoopBreaker{}),
+		WithCircuitBreakerReadyToTrip(func(gobreaker.Counts) bool { return true }),
+	)
+
+	for i := 0; i < 5; i++ {
+		_, err := client.Health(context.Background(), time.Second)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, gobreaker.ErrOpenState)
+	}
+}
+
+func TestClientCircuitBreakerState(t *testing.T) {
+	client := NewClient(WithServer("http://localhost:8108"))
+	assert.Equal(t, "closed", client.CircuitBreakerState())
+
+	client = NewClient(WithServer("http://localhost:8108"), WithCircuitBreaker(circuit.NoopBreaker{}))
+	assert.Equal(t, "", client.CircuitBreakerState())
+
+	client = NewClient(WithNodes([]string{"http://localhost:8108"}), WithCircuitBreakerPerNode(true))
+	assert.Equal(t, "", client.CircuitBreakerState())
+	assert.Equal(t, "closed", client.NodeStatus()[0].CircuitBreakerState)
+}
@@ -16,7 +16,15 @@ type CollectionInterface[T any] interface {
 	Update(context.Context, *api.CollectionUpdateSchema) (*api.CollectionUpdateSchema, error)
 }
 
-var _ CollectionInterface[any] = (*collection[any])(nil)
+// GenericCollectionInterface is a type for Collection API operations on
+// documents of type T
+type GenericCollectionInterface[T any] interface {
+	CollectionInterface[T]
+	// TypedDocuments returns the documents of the collection as documents of type T
+	TypedDocuments() TypedDocumentsInterface[T]
+}
+
+var _ GenericCollectionInterface[any] = (*collection[any])(nil)
 
 // collection is internal implementation of CollectionInterface
 type collection[T any] struct {
@@ -30,7 +38,7 @@ func (c *collection[T]) Retrieve(ctx context.Context) (*api.CollectionResponse,
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -41,7 +49,7 @@ func (c *collection[T]) Delete(ctx context.Context) (*api.CollectionResponse, er
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -50,6 +58,10 @@ func (c *collection[T]) Documents() DocumentsInterface {
 	return &documents{apiClient: c.apiClient, collectionName: c.name}
 }
 
+func (c *collection[T]) TypedDocuments() TypedDocumentsInterface[T] {
+	return &typedDocuments[T]{documents{apiClient: c.apiClient, collectionName: c.name}}
+}
+
 func (c *collection[T]) Document(documentID string) DocumentInterface[T] {
 	return &document[T]{apiClient: c.apiClient, collectionName: c.name, documentID: documentID}
 }
@@ -61,7 +73,7 @@ func (c *collection[T]) Update(ctx context.Context, schema *api.CollectionUpdate
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -12,6 +12,15 @@ type CollectionsInterface interface {
 	Retrieve(ctx context.Context, params *api.GetCollectionsParams) ([]*api.CollectionResponse, error)
 }
 
+// CollectionsPlannerInterface is a type for Collections API operations that
+// also plans schema migrations
+type CollectionsPlannerInterface interface {
+	CollectionsInterface
+	// Plan compares the live schema of the collection named in desired with
+	// desired and returns the changes needed to migrate it, see PlanSchema.
+	Plan(ctx context.Context, desired *api.CollectionSchema) (*SchemaPlan, error)
+}
+
 // collections is internal implementation of CollectionsInterface
 type collections struct {
 	apiClient APIClientInterface
@@ -24,7 +33,7 @@ func (c *collections) Create(ctx context.Context, schema *api.CollectionSchema)
 		return nil, err
 	}
 	if response.JSON201 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON201, nil
 }
@@ -35,7 +44,7 @@ func (c *collections) Retrieve(ctx context.Context, params *api.GetCollectionsPa
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return *response.JSON200, nil
 }
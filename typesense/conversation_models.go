@@ -23,7 +23,7 @@ func (c *conversationModels) Create(ctx context.Context, schema *api.Conversatio
 		return nil, err
 	}
 	if response.JSON201 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON201, nil
 }
@@ -34,7 +34,7 @@ func (c *conversationModels) Retrieve(ctx context.Context) ([]*api.ConversationM
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return *response.JSON200, nil
 }
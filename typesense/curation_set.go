@@ -28,7 +28,7 @@ func (c *curationSet) Retrieve(ctx context.Context) (*api.CurationSetSchema, err
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -39,7 +39,7 @@ func (c *curationSet) Upsert(ctx context.Context, curationSetSchema *api.Curatio
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -50,7 +50,7 @@ func (c *curationSet) Delete(ctx context.Context) (*api.CurationSetDeleteSchema,
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -27,7 +27,7 @@ func (c *curationSets) Upsert(ctx context.Context, curationSetName string, curat
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
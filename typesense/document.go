@@ -32,7 +32,7 @@ func (d *document[T]) Retrieve(ctx context.Context) (resp T, err error) {
 	if !strings.Contains(response.Header.Get("Content-Type"), "json") || response.StatusCode != 200 {
 		body, _ := io.ReadAll(response.Body)
 		response.Body.Close()
-		return resp, &HTTPError{Status: response.StatusCode, Body: body}
+		return resp, newHTTPError(response, body)
 	}
 	err = json.NewDecoder(response.Body).Decode(&resp)
 	if err != nil {
@@ -50,7 +50,7 @@ func (d *document[T]) Update(ctx context.Context, document any, params *api.Docu
 	if !strings.Contains(response.Header.Get("Content-Type"), "json") || response.StatusCode != 200 {
 		body, _ := io.ReadAll(response.Body)
 		response.Body.Close()
-		return resp, &HTTPError{Status: response.StatusCode, Body: body}
+		return resp, newHTTPError(response, body)
 	}
 	err = json.NewDecoder(response.Body).Decode(&resp)
 	if err != nil {
@@ -68,7 +68,7 @@ func (d *document[T]) Delete(ctx context.Context) (resp T, err error) {
 	if !strings.Contains(response.Header.Get("Content-Type"), "json") || response.StatusCode != 200 {
 		body, _ := io.ReadAll(response.Body)
 		response.Body.Close()
-		return resp, &HTTPError{Status: response.StatusCode, Body: body}
+		return resp, newHTTPError(response, body)
 	}
 	err = json.NewDecoder(response.Body).Decode(&resp)
 	if err != nil {
@@ -1,7 +1,6 @@
 package typesense
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -54,7 +53,7 @@ func (d *documents) indexDocument(ctx context.Context, document interface{}, par
 		return nil, err
 	}
 	if response.JSON201 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return *response.JSON201, nil
 }
@@ -70,7 +69,7 @@ func (d *documents) Update(ctx context.Context, updateFields interface{}, params
 		return 0, err
 	}
 	if response.JSON200 == nil {
-		return 0, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return 0, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200.NumUpdated, nil
 }
@@ -86,7 +85,7 @@ func (d *documents) Delete(ctx context.Context, filter *api.DeleteDocumentsParam
 		return 0, err
 	}
 	if response.JSON200 == nil {
-		return 0, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return 0, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200.NumDeleted, nil
 }
@@ -98,7 +97,7 @@ func (d *documents) Search(ctx context.Context, params *api.SearchCollectionPara
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -111,7 +110,7 @@ func (d *documents) Export(ctx context.Context, params *api.ExportDocumentsParam
 	if response.StatusCode != http.StatusOK {
 		defer response.Body.Close()
 		body, _ := io.ReadAll(response.Body)
-		return nil, &HTTPError{Status: response.StatusCode, Body: body}
+		return nil, newHTTPError(response, body)
 	}
 	return response.Body, nil
 }
@@ -146,17 +145,18 @@ func (d *documents) Import(ctx context.Context, documents []interface{}, params
 	defer response.Close()
 
 	var result []*api.ImportDocumentResponse
-	scanner := bufio.NewScanner(response)
-	scanner.Split(bufio.ScanLines)
-	for scanner.Scan() {
+	// the results are in jsonl format; a decoder, unlike a bufio.Scanner, is not
+	// limited in the length of a line, which can hold the whole document
+	decoder := json.NewDecoder(response)
+	for {
 		var docResult *api.ImportDocumentResponse
-		if err := json.Unmarshal(scanner.Bytes(), &docResult); err != nil {
+		if err := decoder.Decode(&docResult); errors.Is(err, io.EOF) {
+			return result, nil
+		} else if err != nil {
 			return result, fmt.Errorf("failed to decode result: %w", err)
 		}
 		result = append(result, docResult)
 	}
-
-	return result, scanner.Err()
 }
 
 func (d *documents) ImportJsonl(ctx context.Context, body io.Reader, params *api.ImportDocumentsParams) (io.ReadCloser, error) {
@@ -169,7 +169,7 @@ func (d *documents) ImportJsonl(ctx context.Context, body io.Reader, params *api
 	if response.StatusCode != http.StatusOK {
 		defer response.Body.Close()
 		body, _ := io.ReadAll(response.Body)
-		return nil, &HTTPError{Status: response.StatusCode, Body: body}
+		return nil, newHTTPError(response, body)
 	}
 	return response.Body, nil
 }
@@ -0,0 +1,121 @@
+package typesense
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors matching the status of an HTTPError, to be used with errors.Is:
+//
+//	if errors.Is(err, typesense.ErrNotFound) {
+//		// create the document
+//	}
+var (
+	// ErrBadRequest matches 400 and 422 responses.
+	ErrBadRequest = errors.New("bad request")
+	// ErrUnauthorized matches 401 and 403 responses.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound matches 404 responses.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict matches 409 responses.
+	ErrConflict = errors.New("conflict")
+	// ErrRateLimited matches 429 responses.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServerUnavailable matches 502, 503 and 504 responses.
+	ErrServerUnavailable = errors.New("server unavailable")
+)
+
+// HTTPError is returned when the server responds with an unexpected status.
+type HTTPError struct {
+	Status int
+	Body   []byte
+	// Message is the error message sent by the server, if any.
+	Message string
+	// Method and Path of the failed request.
+	Method string
+	Path   string
+	// NodeURL is the URL of the node that sent the response.
+	NodeURL string
+	// Attempts is the number of attempts made across the nodes,
+	// or 0 if it is unknown.
+	Attempts int
+	// RetryAfter is the wait time requested by the Retry-After header of a
+	// throttled response, or 0 if there is none.
+	RetryAfter time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("status: %v response: %s", e.Status, string(e.Body))
+}
+
+// Is reports whether the status of the error matches one of the sentinel errors.
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.Status == http.StatusBadRequest || e.Status == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return e.Status == http.StatusUnauthorized || e.Status == http.StatusForbidden
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrConflict:
+		return e.Status == http.StatusConflict
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests
+	case ErrServerUnavailable:
+		return e.Status == http.StatusBadGateway || e.Status == http.StatusServiceUnavailable ||
+			e.Status == http.StatusGatewayTimeout
+	default:
+		return false
+	}
+}
+
+type attemptsContextKey struct{}
+
+// NewHTTPError returns the HTTPError the client returns for a response with
+// the given body, e.g. for middleware that reports failed responses.
+func NewHTTPError(response *http.Response, body []byte) *HTTPError {
+	return newHTTPError(response, body)
+}
+
+// newHTTPError returns an HTTPError for the response, which may be nil, with the
+// message parsed from the body and the metadata of the request that was sent.
+func newHTTPError(response *http.Response, body []byte) *HTTPError {
+	err := &HTTPError{Body: body, Message: parseErrorMessage(body)}
+	if response == nil {
+		return err
+	}
+	err.Status = response.StatusCode
+	if retryAfter, ok := parseRetryAfter(response); ok {
+		err.RetryAfter = retryAfter
+	}
+	if req := response.Request; req != nil {
+		err.Method = req.Method
+		if req.URL != nil {
+			err.Path = req.URL.Path
+			err.NodeURL = req.URL.Scheme + "://" + req.URL.Host
+		}
+		if attempts, ok := req.Context().Value(attemptsContextKey{}).(int); ok {
+			err.Attempts = attempts
+		}
+	}
+	return err
+}
+
+// parseErrorMessage extracts the message from error responses, which look like
+// {"message": "Not Found"}, or {"code": 404, "error": "Not Found"} for multi search.
+func parseErrorMessage(body []byte) string {
+	var errorResponse struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errorResponse); err != nil {
+		return ""
+	}
+	if errorResponse.Message != "" {
+		return errorResponse.Message
+	}
+	return errorResponse.Error
+}
@@ -0,0 +1,108 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPErrorIs(t *testing.T) {
+	sentinels := []error{ErrBadRequest, ErrUnauthorized, ErrNotFound, ErrConflict, ErrRateLimited, ErrServerUnavailable}
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{400, ErrBadRequest},
+		{422, ErrBadRequest},
+		{401, ErrUnauthorized},
+		{403, ErrUnauthorized},
+		{404, ErrNotFound},
+		{409, ErrConflict},
+		{429, ErrRateLimited},
+		{502, ErrServerUnavailable},
+		{503, ErrServerUnavailable},
+		{504, ErrServerUnavailable},
+		{500, nil},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			// wrap the error to make sure errors.Is unwraps it
+			err := fmt.Errorf("request failed: %w", &HTTPError{Status: tt.status})
+			for _, sentinel := range sentinels {
+				assert.Equal(t, sentinel == tt.want, errors.Is(err, sentinel), "sentinel %v", sentinel)
+			}
+		})
+	}
+}
+
+func TestNewHTTPErrorParsesMessage(t *testing.T) {
+	err := newHTTPError(nil, []byte(`{"message": "Could not find a document with id: 123"}`))
+	assert.Equal(t, "Could not find a document with id: 123", err.Message)
+
+	err = newHTTPError(nil, []byte(`{"code":422,"error":"Only upto 250 hits can be fetched per page."}`))
+	assert.Equal(t, "Only upto 250 hits can be fetched per page.", err.Message)
+
+	err = newHTTPError(nil, []byte("Internal server error"))
+	assert.Empty(t, err.Message)
+}
+
+func TestNewHTTPErrorParsesRetryAfter(t *testing.T) {
+	response := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"3"}}}
+	err := newHTTPError(response, nil)
+	assert.Equal(t, 3*time.Second, err.RetryAfter)
+
+	err = newHTTPError(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}, nil)
+	assert.Zero(t, err.RetryAfter)
+}
+
+func TestHTTPErrorContainsRequestMetadata(t *testing.T) {
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	client := NewClient(WithNodes(serverURLs), WithRetryInterval(0))
+	_, err := client.Collection("companies").Retrieve(context.Background())
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	var httpErr *HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.Status)
+	assert.Equal(t, "Not Found", httpErr.Message)
+	assert.Equal(t, http.MethodGet, httpErr.Method)
+	assert.Equal(t, "/collections/companies", httpErr.Path)
+	assert.Equal(t, serverURLs[1], httpErr.NodeURL)
+	assert.Equal(t, 2, httpErr.Attempts)
+}
+
+func TestHTTPErrorFromRawResponseContainsRequestMetadata(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"message": "A document with id 123 already exists."}`))
+	})
+	defer server.Close()
+
+	_, err := client.Collection("companies").Document("123").Retrieve(context.Background())
+
+	assert.ErrorIs(t, err, ErrConflict)
+	var httpErr *HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, "A document with id 123 already exists.", httpErr.Message)
+	assert.Equal(t, "/collections/companies/documents/123", httpErr.Path)
+	assert.Equal(t, server.URL, httpErr.NodeURL)
+	assert.Equal(t, 1, httpErr.Attempts)
+}
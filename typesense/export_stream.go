@@ -0,0 +1,58 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/typesense/typesense-go/v4/typesense/api"
+)
+
+// ExportSeq exports the documents of a collection and yields them decoded into
+// T one at a time, so that only the document being decoded is held in memory.
+// The filter_by, include_fields and exclude_fields of params select the
+// documents and their fields. The export request is made when the sequence is
+// ranged over, and breaking out of the loop closes the response:
+//
+//	for company, err := range typesense.ExportSeq[Company](ctx, client.Collection("companies").Documents(), params) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// The sequence stops at the first error.
+func ExportSeq[T any](ctx context.Context, documents DocumentsInterface, params *api.ExportDocumentsParams) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		body, err := documents.Export(ctx, params)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer body.Close()
+
+		// the documents are exported in jsonl format, i.e. a stream of json
+		// values, which the decoder reads regardless of the length of the lines
+		decoder := json.NewDecoder(body)
+		for {
+			var document T
+			if err := decoder.Decode(&document); err != nil {
+				if !errors.Is(err, io.EOF) {
+					yield(zero, fmt.Errorf("failed to decode exported document: %w", err))
+				}
+				return
+			}
+			if !yield(document, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (d *typedDocuments[T]) ExportSeq(ctx context.Context, params *api.ExportDocumentsParams) iter.Seq2[T, error] {
+	return ExportSeq[T](ctx, &d.documents, params)
+}
@@ -0,0 +1,111 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+)
+
+func TestTypedDocumentsExportSeq(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		validateRequestMetadata(t, r, "/collections/companies/documents/export", http.MethodGet)
+		assert.Equal(t, "num_employees:>100", r.URL.Query().Get("filter_by"))
+		assert.Equal(t, "id,company_name", r.URL.Query().Get("include_fields"))
+		assert.Equal(t, "num_employees", r.URL.Query().Get("exclude_fields"))
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte("{\"id\":\"1\",\"company_name\":\"Stark Industries\"}\n{\"id\":\"2\",\"company_name\":\"Wayne Enterprises\"}"))
+	})
+	defer server.Close()
+
+	params := &api.ExportDocumentsParams{
+		FilterBy:      pointer.String("num_employees:>100"),
+		IncludeFields: pointer.String("id,company_name"),
+		ExcludeFields: pointer.String("num_employees"),
+	}
+	var result []companyDocument
+	for document, err := range GenericCollection[companyDocument](client, "companies").TypedDocuments().ExportSeq(context.Background(), params) {
+		assert.NoError(t, err)
+		result = append(result, document)
+	}
+	assert.Equal(t, []companyDocument{
+		{ID: "1", CompanyName: "Stark Industries"},
+		{ID: "2", CompanyName: "Wayne Enterprises"},
+	}, result)
+}
+
+func TestExportSeqWithDocumentsLargerThanScannerBuffer(t *testing.T) {
+	name := strings.Repeat("a", 100*1024)
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(w, "{\"id\":\"%d\",\"company_name\":\"%s\"}\n", i, name)
+		}
+	})
+	defer server.Close()
+
+	var ids []string
+	for document, err := range ExportSeq[companyDocument](context.Background(), client.Collection("companies").Documents(), nil) {
+		assert.NoError(t, err)
+		assert.Equal(t, name, document.CompanyName)
+		ids = append(ids, document.ID)
+	}
+	assert.Equal(t, []string{"0", "1", "2"}, ids)
+}
+
+func TestExportSeqStopsWhenBreakingOutOfTheLoop(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{\"id\":\"1\"}\n{\"id\":\"2\"}\n{\"id\":\"3\"}\n"))
+	})
+	defer server.Close()
+
+	var result []json.RawMessage
+	for document, err := range ExportSeq[json.RawMessage](context.Background(), client.Collection("companies").Documents(), nil) {
+		assert.NoError(t, err)
+		result = append(result, document)
+		if len(result) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id":"1"}`), json.RawMessage(`{"id":"2"}`)}, result)
+}
+
+func TestExportSeqOnInvalidDocumentYieldsError(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{\"id\":\"1\"}\n{\"id\":\n"))
+	})
+	defer server.Close()
+
+	var ids []string
+	var errs []error
+	for document, err := range ExportSeq[companyDocument](context.Background(), client.Collection("companies").Documents(), nil) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, document.ID)
+	}
+	assert.Equal(t, []string{"1"}, ids)
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "failed to decode exported document")
+}
+
+func TestExportSeqOnHttpStatusErrorCodeYieldsError(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not found."}`))
+	})
+	defer server.Close()
+
+	var errs []error
+	for _, err := range GenericCollection[companyDocument](client, "companies").TypedDocuments().ExportSeq(context.Background(), nil) {
+		errs = append(errs, err)
+	}
+	assert.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrNotFound)
+}
@@ -13,7 +13,7 @@ func (c *Client) Health(ctx context.Context, timeout time.Duration) (bool, error
 		return false, err
 	}
 	if response.JSON200 == nil {
-		return false, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return false, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200.Ok, nil
 }
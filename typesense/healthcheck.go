@@ -0,0 +1,169 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NodeStatus is a snapshot of the state of a node as seen by the client.
+type NodeStatus struct {
+	URL string
+	// Nearest is true for the node configured with WithNearestNode.
+	Nearest bool
+	Healthy bool
+	// LastHealthUpdate is the last time the health of the node was determined,
+	// either by a request or by a background probe.
+	LastHealthUpdate time.Time
+	// LastProbe is zero until the node has been probed in the background.
+	LastProbe        time.Time
+	LastProbeLatency time.Duration
+	LastProbeError   error
+	// CircuitBreakerState is the state of the circuit breaker of the node, e.g. "open",
+	// or empty if the node has none, see WithCircuitBreakerPerNode.
+	CircuitBreakerState string
+}
+
+// nodeProbe holds the outcome of the last background health probe of a node.
+type nodeProbe struct {
+	mu      sync.Mutex
+	time    time.Time
+	latency time.Duration
+	err     error
+}
+
+// startHealthchecks probes the /health endpoint of every node once per interval
+// until Close is called, so that nodes are marked healthy or unhealthy without
+// gambling a user request on them.
+func (a *APICall) startHealthchecks(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	a.stopHealthchecks = func() {
+		cancel()
+		<-done
+	}
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			a.probeNodes(ctx, interval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops the background health checks, if any, and waits for them to finish.
+func (a *APICall) Close() {
+	if a.stopHealthchecks != nil {
+		a.stopHealthchecks()
+	}
+}
+
+func (a *APICall) allNodes() []*Node {
+	if a.nearestNode == nil {
+		return a.nodes
+	}
+	return append([]*Node{a.nearestNode}, a.nodes...)
+}
+
+// probeNodes probes all nodes concurrently, so that a hanging node does
+// not delay the health update of the others.
+func (a *APICall) probeNodes(ctx context.Context, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, node := range a.allNodes() {
+		wg.Add(1)
+		go func(node *Node) {
+			defer wg.Done()
+			a.probeNode(ctx, node, timeout)
+		}(node)
+	}
+	wg.Wait()
+}
+
+func (a *APICall) probeNode(ctx context.Context, node *Node, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := a.checkNodeHealth(ctx, node)
+	latency := time.Since(start)
+	if errors.Is(ctx.Err(), context.Canceled) {
+		// the client is being closed, the outcome says nothing about the node
+		return
+	}
+
+	a.setNodeHealth(ctx, node, err == nil, nil, err)
+
+	node.probe.mu.Lock()
+	node.probe.time = start
+	node.probe.latency = latency
+	node.probe.err = err
+	node.probe.mu.Unlock()
+}
+
+func (a *APICall) checkNodeHealth(ctx context.Context, node *Node) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(node.url, "/")+"/health", nil)
+	if err != nil {
+		return err
+	}
+	response, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return newHTTPError(response, body)
+	}
+	var health struct {
+		Ok bool `json:"ok"`
+	}
+	if err := json.Unmarshal(body, &health); err != nil {
+		return fmt.Errorf("failed to decode health response: %w", err)
+	}
+	if !health.Ok {
+		return fmt.Errorf("node is not ready: %s", body)
+	}
+	return nil
+}
+
+// NodeStatus returns a snapshot of the state of the nearest node, if configured,
+// followed by the other nodes.
+func (a *APICall) NodeStatus() []NodeStatus {
+	nodes := a.allNodes()
+	statuses := make([]NodeStatus, 0, len(nodes))
+	for _, node := range nodes {
+		status := NodeStatus{
+			URL:              node.url,
+			Nearest:          node == a.nearestNode,
+			Healthy:          node.isHealthy.Load(),
+			LastHealthUpdate: time.UnixMilli(node.lastAccessTimestamp.Load()),
+		}
+		if state, ok := breakerState(node.breaker); ok {
+			status.CircuitBreakerState = state.String()
+		}
+		node.probe.mu.Lock()
+		status.LastProbe = node.probe.time
+		status.LastProbeLatency = node.probe.latency
+		status.LastProbeError = node.probe.err
+		node.probe.mu.Unlock()
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
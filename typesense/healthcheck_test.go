@@ -0,0 +1,111 @@
+package typesense
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthProbeMarksNodesHealthyAndUnhealthy(t *testing.T) {
+	var nodeRecovered atomic.Bool
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, r *http.Request) {
+			validateRequestMetadata(t, r, "/health", http.MethodGet)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":true}`))
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			validateRequestMetadata(t, r, "/health", http.MethodGet)
+			if nodeRecovered.Load() {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"ok":true}`))
+				return
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"ok":false}`))
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			validateRequestMetadata(t, r, "/health", http.MethodGet)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":false,"resource_error":"OUT_OF_MEMORY"}`))
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	client := NewClient(
+		WithNearestNode(serverURLs[0]),
+		WithNodes(serverURLs[1:]),
+		WithHealthProbeInterval(10*time.Millisecond),
+	)
+	defer client.Close()
+
+	assert.Eventually(t, func() bool {
+		statuses := client.NodeStatus()
+		return !statuses[1].LastProbe.IsZero() && !statuses[2].LastProbe.IsZero()
+	}, time.Second, 5*time.Millisecond)
+
+	statuses := client.NodeStatus()
+	assert.Len(t, statuses, 3)
+
+	assert.Equal(t, serverURLs[0], statuses[0].URL)
+	assert.True(t, statuses[0].Nearest)
+	assert.True(t, statuses[0].Healthy)
+
+	assert.Equal(t, serverURLs[1], statuses[1].URL)
+	assert.False(t, statuses[1].Nearest)
+	assert.False(t, statuses[1].Healthy)
+	assert.ErrorContains(t, statuses[1].LastProbeError, "status: 503")
+
+	assert.False(t, statuses[2].Healthy)
+	assert.ErrorContains(t, statuses[2].LastProbeError, "OUT_OF_MEMORY")
+
+	nodeRecovered.Store(true)
+
+	assert.Eventually(t, func() bool {
+		status := client.NodeStatus()[1]
+		return status.Healthy && status.LastProbeError == nil && status.LastProbeLatency > 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestHealthProbeStopsWhenClientIsClosed(t *testing.T) {
+	var probes atomic.Int64
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			probes.Add(1)
+			w.Write([]byte(`{"ok":true}`))
+		},
+	})
+	defer servers[0].Close()
+
+	client := NewClient(
+		WithNodes(serverURLs),
+		WithHealthProbeInterval(5*time.Millisecond),
+	)
+	assert.Eventually(t, func() bool { return probes.Load() > 0 }, time.Second, time.Millisecond)
+
+	assert.NoError(t, client.Close())
+	time.Sleep(20 * time.Millisecond)
+	probesAfterClose := probes.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, probesAfterClose, probes.Load())
+}
+
+func TestNodeStatusWithoutHealthProbe(t *testing.T) {
+	client := NewClient(WithNodes([]string{"http://localhost:8108", "http://localhost:8109"}))
+	defer client.Close()
+
+	statuses := client.NodeStatus()
+	assert.Len(t, statuses, 2)
+	for _, status := range statuses {
+		assert.True(t, status.Healthy)
+		assert.True(t, status.LastProbe.IsZero())
+		assert.False(t, status.LastHealthUpdate.IsZero())
+	}
+}
@@ -0,0 +1,153 @@
+package typesense
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+type hedgeDelayContextKey struct{}
+
+// ContextWithHedgeDelay overrides the hedge delay configured with WithHedgeDelay
+// for the searches made with the returned context. A delay of 0 disables hedging.
+func ContextWithHedgeDelay(ctx context.Context, delay time.Duration) context.Context {
+	return context.WithValue(ctx, hedgeDelayContextKey{}, delay)
+}
+
+// isHedgeableRequest reports whether the request is a read-only search,
+// i.e. a collection search or a multi search.
+func isHedgeableRequest(req *http.Request) bool {
+	segments := requestPathSegments(req)
+	switch req.Method {
+	case http.MethodGet:
+		return len(segments) == 4 && segments[0] == "collections" && segments[2] == "documents" && segments[3] == "search"
+	case http.MethodPost:
+		return len(segments) == 1 && segments[0] == "multi_search"
+	default:
+		return false
+	}
+}
+
+// hedgeDelayFor returns how long to wait for a response before hedging the
+// request to another node, or 0 if the request must not be hedged.
+func (a *APICall) hedgeDelayFor(req *http.Request) time.Duration {
+	if len(a.allNodes()) < 2 || !isHedgeableRequest(req) {
+		return 0
+	}
+	if delay, ok := req.Context().Value(hedgeDelayContextKey{}).(time.Duration); ok {
+		return delay
+	}
+	return a.hedgeDelay
+}
+
+type hedgedAttempt struct {
+	node     *Node
+	response *http.Response
+	err      error
+	cancel   context.CancelFunc
+}
+
+func (h *hedgedAttempt) succeeded() bool {
+	return h.err == nil && h.response.StatusCode >= 1 && h.response.StatusCode < 500
+}
+
+// release discards the outcome of an attempt that is not returned to the caller.
+func (h *hedgedAttempt) release() {
+	closeResponseBody(h.response)
+	h.cancel()
+}
+
+// cancelOnCloseBody cancels the context of a hedged attempt once its response body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// doHedged sends the request to the next node and, if no response has arrived
+// after delay, sends it to another node as well. The first successful response
+// wins and the other attempt is cancelled. If all attempts fail, the failure of
+// the last one is returned.
+func (a *APICall) doHedged(req *http.Request, delay time.Duration, info AttemptInfo) (*Node, *http.Response, error) {
+	attempts := make(chan *hedgedAttempt, 2)
+	launched := make([]*hedgedAttempt, 0, 2)
+	launch := func(node *Node, hedged bool) {
+		ctx, cancel := context.WithCancel(req.Context())
+		info.NodeURL = node.url
+		info.Hedged = hedged
+		ctx = context.WithValue(ctx, attemptInfoContextKey{}, info)
+		attemptReq := req.Clone(ctx)
+		replaceRequestHostname(attemptReq, node.url)
+		attempt := &hedgedAttempt{node: node, cancel: cancel}
+		launched = append(launched, attempt)
+		go func() {
+			if attempt.err = rewindBody(attemptReq); attempt.err == nil {
+				attempt.response, attempt.err = a.doWithNode(node, attemptReq)
+			}
+			attempts <- attempt
+		}()
+	}
+
+	primary := a.getNextNode()
+	launch(primary, false)
+	inFlight := 1
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	hedgeTimeout := timer.C
+
+	for {
+		select {
+		case <-hedgeTimeout:
+			hedgeTimeout = nil
+			if node := a.getHedgeNode(primary); node != nil {
+				launch(node, true)
+				inFlight++
+			}
+		case attempt := <-attempts:
+			inFlight--
+			if !attempt.succeeded() && inFlight > 0 {
+				// wait for the other attempt, which may still succeed
+				if req.Context().Err() == nil && (attempt.err != nil || attempt.response.StatusCode >= 500) {
+					a.setNodeHealth(req.Context(), attempt.node, UNHEALTHY, attempt.response, attempt.err)
+				}
+				attempt.release()
+				continue
+			}
+			if inFlight > 0 {
+				// cancel the losing attempt and discard its outcome once it returns
+				for _, other := range launched {
+					if other != attempt {
+						other.cancel()
+					}
+				}
+				go func() {
+					(<-attempts).release()
+				}()
+			}
+			if attempt.err != nil {
+				attempt.cancel()
+				return attempt.node, nil, attempt.err
+			}
+			attempt.response.Body = &cancelOnCloseBody{ReadCloser: attempt.response.Body, cancel: attempt.cancel}
+			return attempt.node, attempt.response, nil
+		}
+	}
+}
+
+// getHedgeNode returns an available node other than the given one, or nil if there is none.
+func (a *APICall) getHedgeNode(exclude *Node) *Node {
+	for i := 0; i <= len(a.nodes); i++ {
+		node := a.nodeSelector.Select(a.nodes)
+		if node != exclude && node.Available() {
+			return node
+		}
+	}
+	return nil
+}
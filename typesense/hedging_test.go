@@ -0,0 +1,188 @@
+package typesense
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+)
+
+func TestIsHedgeableRequest(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		want   bool
+	}{
+		{http.MethodGet, "http://example.com/collections/companies/documents/search?q=stark", true},
+		{http.MethodPost, "http://example.com/multi_search", true},
+		{http.MethodGet, "http://example.com/collections/companies/documents/123", false},
+		{http.MethodGet, "http://example.com/collections", false},
+		{http.MethodPost, "http://example.com/collections/companies/documents", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, isHedgeableRequest(req))
+		})
+	}
+}
+
+// newHedgingServers returns a slow node, which blocks until the request is cancelled
+// or a second has passed, followed by a fast node.
+func newHedgingServers(t *testing.T, slowCancelled *atomic.Bool) []string {
+	t.Helper()
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+				slowCancelled.Store(true)
+				return
+			case <-time.After(time.Second):
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"found":1,"hits":[]}`))
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"found":2,"hits":[]}`))
+		},
+	})
+	t.Cleanup(func() {
+		for _, server := range servers {
+			server.Close()
+		}
+	})
+	return serverURLs
+}
+
+func TestApiCallHedgesSlowSearch(t *testing.T) {
+	var slowCancelled atomic.Bool
+	serverURLs := newHedgingServers(t, &slowCancelled)
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             serverURLs,
+			HedgeDelay:        10 * time.Millisecond,
+			ConnectionTimeout: 5 * time.Second,
+		},
+	)
+
+	start := time.Now()
+	res, err := apiCall.Do(newHTTPRequest(t, "http://example.com/collections/companies/documents/search?q=stark"))
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+	assert.JSONEq(t, `{"found":2,"hits":[]}`, string(body))
+
+	assert.Eventually(t, slowCancelled.Load, time.Second, 5*time.Millisecond)
+}
+
+func TestApiCallHedgedAttemptsSendRequestBody(t *testing.T) {
+	var bodies [2]atomic.Value
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies[0].Store(string(body))
+			<-r.Context().Done()
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies[1].Store(string(body))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"results":[]}`))
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             serverURLs,
+			HedgeDelay:        10 * time.Millisecond,
+			ConnectionTimeout: 5 * time.Second,
+		},
+	)
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/multi_search", strings.NewReader(`{"searches":[]}`))
+	assert.NoError(t, err)
+	res, err := apiCall.Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+
+	assert.Equal(t, `{"searches":[]}`, bodies[0].Load())
+	assert.Equal(t, `{"searches":[]}`, bodies[1].Load())
+}
+
+func TestApiCallDoesNotHedgeWhenDisabledPerCall(t *testing.T) {
+	var slowCancelled atomic.Bool
+	serverURLs := newHedgingServers(t, &slowCancelled)
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             serverURLs,
+			HedgeDelay:        10 * time.Millisecond,
+			ConnectionTimeout: 5 * time.Second,
+		},
+	)
+
+	ctx := ContextWithHedgeDelay(context.Background(), 0)
+	req := newHTTPRequest(t, "http://example.com/collections/companies/documents/search?q=stark").WithContext(ctx)
+
+	start := time.Now()
+	res, err := apiCall.Do(req)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"found":1,"hits":[]}`, string(body))
+	assert.False(t, slowCancelled.Load())
+}
+
+func TestApiCallDoesNotHedgeNonSearchRequests(t *testing.T) {
+	var slowCancelled atomic.Bool
+	serverURLs := newHedgingServers(t, &slowCancelled)
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:             serverURLs,
+			HedgeDelay:        10 * time.Millisecond,
+			ConnectionTimeout: 5 * time.Second,
+		},
+	)
+
+	start := time.Now()
+	res, err := apiCall.Do(newHTTPRequest(t, "http://example.com/collections/companies/documents/123"))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestClientSearchWithHedgeDelayPerCall(t *testing.T) {
+	var slowCancelled atomic.Bool
+	serverURLs := newHedgingServers(t, &slowCancelled)
+
+	client := NewClient(WithNodes(serverURLs))
+
+	ctx := ContextWithHedgeDelay(context.Background(), 10*time.Millisecond)
+	start := time.Now()
+	result, err := client.Collection("companies").Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:       pointer.String("stark"),
+		QueryBy: pointer.String("company_name"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *result.Found)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
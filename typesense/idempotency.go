@@ -0,0 +1,60 @@
+package typesense
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IdempotencyClassifier reports whether replaying the request against another
+// node is safe, i.e. applying it twice has the same effect as applying it once.
+type IdempotencyClassifier func(req *http.Request) bool
+
+// DefaultIdempotencyClassifier treats reads, searches, deletes and PUT requests as
+// idempotent. Document writes are idempotent unless they create documents
+// (action=create, which is the default for indexing and importing), since a
+// replayed create can duplicate a write the first node has already applied.
+// Any other POST or PATCH request, e.g. creating a collection or an API key or
+// sending analytics events, is not idempotent.
+func DefaultIdempotencyClassifier(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	segments := requestPathSegments(req)
+	switch {
+	case req.Method == http.MethodPost && len(segments) == 1 && segments[0] == "multi_search":
+		return true
+	case len(segments) >= 3 && segments[0] == "collections" && segments[2] == "documents":
+		if req.Method == http.MethodPatch {
+			// partial updates set the same values again
+			return true
+		}
+		isIndex := len(segments) == 3
+		isImport := len(segments) == 4 && segments[3] == "import"
+		if req.Method == http.MethodPost && (isIndex || isImport) {
+			action := req.URL.Query().Get("action")
+			return action != "" && action != "create"
+		}
+	}
+	return false
+}
+
+func requestPathSegments(req *http.Request) []string {
+	return strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+}
+
+// isRequestUnprocessed reports whether the failed attempt is known to not have
+// been applied by the server, so that it can be replayed even if it is not idempotent.
+func isRequestUnprocessed(response *http.Response, err error) bool {
+	if err != nil {
+		// the node's circuit breaker is open or the connection could not be
+		// established, so the request was never sent
+		var rejectedErr *nodeBreakerRejectedError
+		var opErr *net.OpError
+		return errors.As(err, &rejectedErr) || (errors.As(err, &opErr) && opErr.Op == "dial")
+	}
+	return isThrottled(response)
+}
@@ -0,0 +1,51 @@
+package typesense
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultIdempotencyClassifier(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		want   bool
+	}{
+		{http.MethodGet, "http://example.com/collections/companies/documents/search?q=stark", true},
+		{http.MethodPost, "http://example.com/multi_search", true},
+		{http.MethodPut, "http://example.com/aliases/companies", true},
+		{http.MethodDelete, "http://example.com/collections/companies/documents/123", true},
+		{http.MethodPatch, "http://example.com/collections/companies/documents/123", true},
+		{http.MethodPatch, "http://example.com/collections/companies/documents?filter_by=num_employees:>100", true},
+		{http.MethodPost, "http://example.com/collections/companies/documents", false},
+		{http.MethodPost, "http://example.com/collections/companies/documents?action=create", false},
+		{http.MethodPost, "http://example.com/collections/companies/documents?action=upsert", true},
+		{http.MethodPost, "http://example.com/collections/companies/documents/import?action=create", false},
+		{http.MethodPost, "http://example.com/collections/companies/documents/import?action=emplace", true},
+		{http.MethodPost, "http://example.com/collections", false},
+		{http.MethodPatch, "http://example.com/collections/companies", false},
+		{http.MethodPost, "http://example.com/analytics/events", false},
+		{http.MethodPost, "http://example.com/keys", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, DefaultIdempotencyClassifier(req))
+		})
+	}
+}
+
+func TestIsRequestUnprocessed(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+
+	assert.True(t, isRequestUnprocessed(nil, dialErr))
+	assert.False(t, isRequestUnprocessed(nil, readErr))
+	assert.True(t, isRequestUnprocessed(&http.Response{StatusCode: 429, Header: http.Header{}}, nil))
+	assert.False(t, isRequestUnprocessed(&http.Response{StatusCode: 500, Header: http.Header{}}, nil))
+}
@@ -0,0 +1,199 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/typesense/typesense-go/v4/typesense/api"
+)
+
+const DefaultImportMaxRetries = 5
+
+// ImportErrorClass is the kind of failure of a document that was not imported.
+type ImportErrorClass string
+
+const (
+	// ImportErrorTransient means the server could not accept the document at
+	// the time, e.g. because it is running out of memory or disk or is lagging
+	// behind, and importing it again later may succeed.
+	ImportErrorTransient ImportErrorClass = "transient"
+	// ImportErrorInvalid means the document is malformed or violates the schema.
+	ImportErrorInvalid ImportErrorClass = "invalid"
+	// ImportErrorConflict means a document with the same id already exists.
+	ImportErrorConflict ImportErrorClass = "conflict"
+	// ImportErrorNotFound means the document to be updated does not exist.
+	ImportErrorNotFound ImportErrorClass = "not_found"
+	// ImportErrorOther is any other failure.
+	ImportErrorOther ImportErrorClass = "other"
+)
+
+// transientImportErrors are parts of the error messages of documents that the
+// server could not accept because of its state rather than their content.
+var transientImportErrors = []string{
+	"rejecting write",
+	"running out of resource",
+	"not ready or lagging",
+	"timed out",
+	"too many requests",
+}
+
+// ClassifyImportError returns the kind of failure of a document that was not
+// imported. Messages of a server that cannot accept writes are transient
+// whatever their status code, since the server reports them with 422. Otherwise
+// the status code decides, and the error message only if there is no code.
+func ClassifyImportError(result *api.ImportDocumentResponse) ImportErrorClass {
+	message := strings.ToLower(result.Error)
+	for _, transient := range transientImportErrors {
+		if strings.Contains(message, transient) {
+			return ImportErrorTransient
+		}
+	}
+	switch {
+	case result.Code == http.StatusTooManyRequests || result.Code >= http.StatusInternalServerError:
+		return ImportErrorTransient
+	case result.Code == http.StatusConflict:
+		return ImportErrorConflict
+	case result.Code == http.StatusNotFound:
+		return ImportErrorNotFound
+	case result.Code == http.StatusBadRequest || result.Code == http.StatusUnprocessableEntity:
+		return ImportErrorInvalid
+	case result.Code != 0:
+		return ImportErrorOther
+	case strings.Contains(message, "already exists"):
+		return ImportErrorConflict
+	case strings.Contains(message, "not found"):
+		return ImportErrorNotFound
+	default:
+		return ImportErrorOther
+	}
+}
+
+// ImportReport is the outcome of TypedDocumentsInterface.ImportWithRetry.
+type ImportReport[T any] struct {
+	// Imported is the number of documents imported successfully.
+	Imported int
+	// Retried is the number of times a document was imported again after a
+	// transient failure.
+	Retried int
+	// Rejected are the documents that could not be imported, in input order.
+	Rejected []RejectedDocument[T]
+}
+
+// RejectedDocument is a document that could not be imported.
+type RejectedDocument[T any] struct {
+	// Index is the position of the document in the imported documents.
+	Index    int
+	Document T
+	// Result is the result of the last attempt to import the document. It is
+	// nil if the import request of the document failed.
+	Result *api.ImportDocumentResponse
+	Class  ImportErrorClass
+}
+
+// WithImportMaxRetries sets how often ImportWithRetry imports documents again
+// after transient failures, including import requests that were rate limited
+// or found the server unavailable, which the client does not retry on its own
+// for ImportWithRetry. Defaults to DefaultImportMaxRetries.
+func WithImportMaxRetries(n int) ImportOption {
+	return func(c *importConfig) {
+		c.maxRetries = n
+	}
+}
+
+// WithImportBackoff sets the policy deciding how long ImportWithRetry waits
+// before importing documents again. Defaults to an ExponentialBackoffRetryPolicy.
+func WithImportBackoff(policy RetryPolicy) ImportOption {
+	return func(c *importConfig) {
+		c.backoff = policy
+	}
+}
+
+var errTransientImportFailure = errors.New("transient import failure")
+
+func (d *typedDocuments[T]) ImportWithRetry(ctx context.Context, documents []T, params *api.ImportDocumentsParams, opts ...ImportOption) (*ImportReport[T], error) {
+	config := newImportConfig(opts)
+	report := &ImportReport[T]{}
+	// 429 and 503 responses are retried here with the backoff of the config,
+	// on top of which the client must not retry them as well
+	ctx = contextWithoutOverloadRetries(ctx)
+	pending := make([]RejectedDocument[T], len(documents))
+	for i, document := range documents {
+		pending[i] = RejectedDocument[T]{Index: i, Document: document}
+	}
+
+	start := time.Now()
+	for attempt := 1; len(pending) > 0; attempt++ {
+		retry, err := d.importRound(ctx, pending, params, config, attempt <= config.maxRetries, report)
+		if err != nil {
+			return report, err
+		}
+		if len(retry) == 0 {
+			break
+		}
+		wait, ok := config.backoff.NextRetry(&RetryAttempt{Attempt: attempt, Elapsed: time.Since(start), Err: errTransientImportFailure})
+		if !ok {
+			report.Rejected = append(report.Rejected, retry...)
+			break
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return report, err
+		}
+		report.Retried += len(retry)
+		pending = retry
+	}
+
+	slices.SortFunc(report.Rejected, func(a, b RejectedDocument[T]) int {
+		return a.Index - b.Index
+	})
+	return report, nil
+}
+
+// importRound imports the pending documents, adding the imported and rejected
+// ones to the report, and returns the documents that failed transiently and
+// are to be retried if canRetry is true.
+func (d *typedDocuments[T]) importRound(ctx context.Context, pending []RejectedDocument[T], params *api.ImportDocumentsParams, config *importConfig, canRetry bool, report *ImportReport[T]) ([]RejectedDocument[T], error) {
+	source := func(yield func(T, error) bool) {
+		for _, document := range pending {
+			if !yield(document.Document, nil) {
+				return
+			}
+		}
+	}
+
+	var retry []RejectedDocument[T]
+	received := 0
+	for result, err := range importSeq(ctx, &d.documents, source, params, config) {
+		if err != nil {
+			if !canRetry || !(errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServerUnavailable)) {
+				return nil, err
+			}
+			// the documents without a result were not imported by the failed request
+			for _, document := range pending[received:] {
+				document.Class = ImportErrorTransient
+				retry = append(retry, document)
+			}
+			return retry, nil
+		}
+		if received == len(pending) {
+			return nil, errors.New("failed to decode result: more results than documents")
+		}
+		document := pending[received]
+		received++
+		if result.Success {
+			report.Imported++
+			continue
+		}
+		document.Result = result
+		document.Class = ClassifyImportError(result)
+		if document.Class == ImportErrorTransient && canRetry {
+			retry = append(retry, document)
+			continue
+		}
+		report.Rejected = append(report.Rejected, document)
+	}
+	return retry, nil
+}
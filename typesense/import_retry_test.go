@@ -0,0 +1,229 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+)
+
+func TestClassifyImportError(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *api.ImportDocumentResponse
+		want   ImportErrorClass
+	}{
+		{"out of resource", &api.ImportDocumentResponse{Code: 422, Error: "Rejecting write: running out of resource type: OUT_OF_MEMORY"}, ImportErrorTransient},
+		{"lagging", &api.ImportDocumentResponse{Error: "Not Ready or Lagging"}, ImportErrorTransient},
+		{"server error", &api.ImportDocumentResponse{Code: 500, Error: "Internal error"}, ImportErrorTransient},
+		{"rate limited", &api.ImportDocumentResponse{Code: 429}, ImportErrorTransient},
+		{"schema violation", &api.ImportDocumentResponse{Code: 400, Error: "Field `num_employees` must be an int32."}, ImportErrorInvalid},
+		{"missing field", &api.ImportDocumentResponse{Code: 400, Error: "Field `x` has been declared in the schema, but is not found in the document."}, ImportErrorInvalid},
+		{"bad json", &api.ImportDocumentResponse{Code: 422, Error: "Bad JSON."}, ImportErrorInvalid},
+		{"conflict", &api.ImportDocumentResponse{Code: 409, Error: "A document with id 123 already exists."}, ImportErrorConflict},
+		{"conflict without code", &api.ImportDocumentResponse{Error: "A document with id 123 already exists."}, ImportErrorConflict},
+		{"not found", &api.ImportDocumentResponse{Code: 404, Error: "Could not find a document with id: 123"}, ImportErrorNotFound},
+		{"not found without code", &api.ImportDocumentResponse{Error: "Document not found."}, ImportErrorNotFound},
+		{"other", &api.ImportDocumentResponse{Error: "Something unexpected"}, ImportErrorOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyImportError(tt.result))
+		})
+	}
+}
+
+// newRetryTestServer returns a client whose import requests are answered by
+// respond with the ids of the documents and the number of the request, after
+// the ids have been recorded.
+func newRetryTestServer(t *testing.T, respond func(w http.ResponseWriter, request int, ids []string)) (*Client, func() [][]string) {
+	lines := importedLines(t)
+	importedIDs := func(r *http.Request) []string {
+		var ids []string
+		for _, line := range lines(r) {
+			var document companyDocument
+			assert.NoError(t, json.Unmarshal([]byte(line), &document))
+			ids = append(ids, document.ID)
+		}
+		return ids
+	}
+	return newRecordingTestClient(t, importedIDs, func(w http.ResponseWriter, _ *http.Request, request int, ids []string) {
+		respond(w, request, ids)
+	})
+}
+
+func retryTestDocuments(n int) []companyDocument {
+	var documents []companyDocument
+	for document := range companyDocuments(n) {
+		documents = append(documents, document)
+	}
+	return documents
+}
+
+func TestTypedDocumentsImportWithRetry(t *testing.T) {
+	client, requests := newRetryTestServer(t, func(w http.ResponseWriter, request int, ids []string) {
+		for _, id := range ids {
+			switch {
+			case id == "1":
+				fmt.Fprintln(w, `{"success": false, "code": 400, "error": "Field num_employees must be an int32."}`)
+			case id == "3":
+				fmt.Fprintln(w, `{"success": false, "code": 409, "error": "A document with id 3 already exists."}`)
+			case (id == "2" || id == "4") && request == 1:
+				fmt.Fprintln(w, `{"success": false, "code": 422, "error": "Rejecting write: running out of resource type: OUT_OF_MEMORY"}`)
+			default:
+				fmt.Fprintln(w, `{"success": true}`)
+			}
+		}
+	})
+	documents := retryTestDocuments(5)
+
+	report, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		ImportWithRetry(context.Background(), documents, nil,
+			WithImportBackoff(&FixedIntervalRetryPolicy{Interval: time.Millisecond}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0", "1", "2", "3", "4"}, {"2", "4"}}, requests())
+	assert.Equal(t, 3, report.Imported)
+	assert.Equal(t, 2, report.Retried)
+	assert.Equal(t, []RejectedDocument[companyDocument]{
+		{
+			Index:    1,
+			Document: documents[1],
+			Result:   &api.ImportDocumentResponse{Code: 400, Error: "Field num_employees must be an int32."},
+			Class:    ImportErrorInvalid,
+		},
+		{
+			Index:    3,
+			Document: documents[3],
+			Result:   &api.ImportDocumentResponse{Code: 409, Error: "A document with id 3 already exists."},
+			Class:    ImportErrorConflict,
+		},
+	}, report.Rejected)
+}
+
+func TestTypedDocumentsImportWithRetryExhaustsRetries(t *testing.T) {
+	client, requests := newRetryTestServer(t, func(w http.ResponseWriter, request int, ids []string) {
+		for _, id := range ids {
+			if id == "1" {
+				fmt.Fprintln(w, `{"success": false, "error": "Not Ready or Lagging"}`)
+				continue
+			}
+			fmt.Fprintln(w, `{"success": true}`)
+		}
+	})
+	documents := retryTestDocuments(3)
+
+	report, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		ImportWithRetry(context.Background(), documents, nil,
+			WithImportMaxRetries(2),
+			WithImportBackoff(&FixedIntervalRetryPolicy{Interval: time.Millisecond}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0", "1", "2"}, {"1"}, {"1"}}, requests())
+	assert.Equal(t, 2, report.Imported)
+	assert.Equal(t, 2, report.Retried)
+	assert.Equal(t, []RejectedDocument[companyDocument]{
+		{
+			Index:    1,
+			Document: documents[1],
+			Result:   &api.ImportDocumentResponse{Error: "Not Ready or Lagging"},
+			Class:    ImportErrorTransient,
+		},
+	}, report.Rejected)
+}
+
+func TestTypedDocumentsImportWithRetryRetriesUnavailableServer(t *testing.T) {
+	client, requests := newRetryTestServer(t, func(w http.ResponseWriter, request int, ids []string) {
+		if request == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		for range ids {
+			fmt.Fprintln(w, `{"success": true}`)
+		}
+	})
+	documents := retryTestDocuments(4)
+
+	report, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		ImportWithRetry(context.Background(), documents, nil,
+			WithImportChunkSize(2),
+			WithImportBackoff(&FixedIntervalRetryPolicy{Interval: time.Millisecond}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0", "1"}, {"2", "3"}, {"2", "3"}}, requests())
+	assert.Equal(t, 4, report.Imported)
+	assert.Equal(t, 2, report.Retried)
+	assert.Empty(t, report.Rejected)
+}
+
+func TestTypedDocumentsImportWithRetryReturnsRequestError(t *testing.T) {
+	client, requests := newRetryTestServer(t, func(w http.ResponseWriter, request int, ids []string) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "Not Found"}`)
+	})
+
+	report, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		ImportWithRetry(context.Background(), retryTestDocuments(2), nil)
+
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Len(t, requests(), 1)
+	assert.Equal(t, 0, report.Imported)
+}
+
+func TestTypedDocumentsImportWithRetryStopsWhenContextIsDone(t *testing.T) {
+	client, _ := newRetryTestServer(t, func(w http.ResponseWriter, request int, ids []string) {
+		for range ids {
+			fmt.Fprintln(w, `{"success": false, "error": "Rejecting write: running out of resource type: OUT_OF_DISK"}`)
+		}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		ImportWithRetry(ctx, retryTestDocuments(2), nil,
+			WithImportBackoff(&FixedIntervalRetryPolicy{Interval: time.Minute}))
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestTypedDocumentsImportWithRetryReturnsErrorOnExtraResults(t *testing.T) {
+	client, _ := newRetryTestServer(t, func(w http.ResponseWriter, request int, ids []string) {
+		for range len(ids) + 1 {
+			fmt.Fprintln(w, `{"success": true}`)
+		}
+	})
+
+	report, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		ImportWithRetry(context.Background(), retryTestDocuments(2), nil)
+
+	assert.EqualError(t, err, "failed to decode result: more results than documents")
+	assert.Equal(t, 2, report.Imported)
+}
+
+func TestTypedDocumentsImportWithRetryIsTheOnlyRetryOfRateLimitedRequests(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	// the client would send every import request 5 times on its own
+	client := NewClient(WithNodes([]string{server.URL}), WithNumRetries(5), WithRetryInterval(0))
+	defer client.Close()
+
+	_, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		ImportWithRetry(context.Background(), retryTestDocuments(2), nil,
+			WithImportMaxRetries(2),
+			WithImportBackoff(&FixedIntervalRetryPolicy{Interval: time.Millisecond}))
+
+	assert.True(t, errors.Is(err, ErrRateLimited))
+	assert.Equal(t, int64(3), requests.Load())
+}
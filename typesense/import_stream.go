@@ -0,0 +1,176 @@
+package typesense
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/typesense/typesense-go/v4/typesense/api"
+)
+
+const (
+	defaultImportChunkSize  = 1000
+	defaultImportChunkBytes = 16 << 20
+)
+
+// ImportOption configures how streamed documents are split into import requests.
+type ImportOption func(*importConfig)
+
+type importConfig struct {
+	chunkSize  int
+	chunkBytes int
+	maxRetries int
+	backoff    RetryPolicy
+}
+
+// WithImportChunkSize sets the maximum number of documents sent per import request.
+func WithImportChunkSize(n int) ImportOption {
+	return func(c *importConfig) {
+		c.chunkSize = n
+	}
+}
+
+// WithImportChunkBytes sets the maximum size of the body of an import request.
+// A document that is larger on its own is sent in a request of its own.
+func WithImportChunkBytes(n int) ImportOption {
+	return func(c *importConfig) {
+		c.chunkBytes = n
+	}
+}
+
+func newImportConfig(opts []ImportOption) *importConfig {
+	config := &importConfig{
+		chunkSize:  defaultImportChunkSize,
+		chunkBytes: defaultImportChunkBytes,
+		maxRetries: DefaultImportMaxRetries,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.backoff == nil {
+		config.backoff = NewExponentialBackoffRetryPolicy()
+	}
+	if config.chunkSize <= 0 {
+		config.chunkSize = defaultImportChunkSize
+	}
+	if config.chunkBytes <= 0 {
+		config.chunkBytes = defaultImportChunkBytes
+	}
+	return config
+}
+
+func (d *typedDocuments[T]) ImportSeq(ctx context.Context, documents iter.Seq[T], params *api.ImportDocumentsParams, opts ...ImportOption) iter.Seq2[*api.ImportDocumentResponse, error] {
+	return importSeq(ctx, &d.documents, withoutErrors(documents), params, newImportConfig(opts))
+}
+
+func (d *typedDocuments[T]) ImportChan(ctx context.Context, documents <-chan T, params *api.ImportDocumentsParams, opts ...ImportOption) iter.Seq2[*api.ImportDocumentResponse, error] {
+	return importSeq(ctx, &d.documents, withoutErrors(chanSeq(ctx, documents)), params, newImportConfig(opts))
+}
+
+// importSeq imports the documents of source in chunks, holding only one
+// chunk in memory at a time, and yields the result of every document as soon as
+// it has been decoded from the response. It stops at the first error, which is
+// either yielded by source or returned by an import request.
+func importSeq[T any](ctx context.Context, d *documents, source iter.Seq2[T, error], params *api.ImportDocumentsParams, config *importConfig) iter.Seq2[*api.ImportDocumentResponse, error] {
+	return func(yield func(*api.ImportDocumentResponse, error) bool) {
+		chunk := &bytes.Buffer{}
+		chunkDocuments := 0
+		// flush imports the chunk and reports whether to continue
+		flush := func() bool {
+			if chunkDocuments == 0 {
+				return true
+			}
+			// every chunk gets a new buffer, since a hedged attempt that lost
+			// the race may still be reading the previous one
+			body := chunk
+			chunk = &bytes.Buffer{}
+			chunkDocuments = 0
+			return importChunk(ctx, d, body, params, yield)
+		}
+
+		for document, err := range source {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			data, err := json.Marshal(document)
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to encode document: %w", err))
+				return
+			}
+			if chunkDocuments > 0 && chunk.Len()+len(data)+1 > config.chunkBytes && !flush() {
+				return
+			}
+			chunk.Write(data)
+			chunk.WriteByte('\n')
+			chunkDocuments++
+			if chunkDocuments >= config.chunkSize && !flush() {
+				return
+			}
+		}
+		if flush() && ctx.Err() != nil {
+			// a channel is no longer read once ctx is done
+			yield(nil, ctx.Err())
+		}
+	}
+}
+
+// importChunk imports the JSONL documents of body and yields their results.
+// It reports whether the import should continue.
+func importChunk(ctx context.Context, d *documents, body *bytes.Buffer, params *api.ImportDocumentsParams, yield func(*api.ImportDocumentResponse, error) bool) bool {
+	chunkParams := &api.ImportDocumentsParams{}
+	if params != nil {
+		*chunkParams = *params
+	}
+	response, err := d.ImportJsonl(ctx, body, chunkParams)
+	if err != nil {
+		yield(nil, err)
+		return false
+	}
+	defer response.Close()
+
+	decoder := json.NewDecoder(response)
+	for {
+		var result *api.ImportDocumentResponse
+		if err := decoder.Decode(&result); err != nil {
+			if err == io.EOF {
+				return true
+			}
+			yield(nil, fmt.Errorf("failed to decode result: %w", err))
+			return false
+		}
+		if !yield(result, nil) {
+			return false
+		}
+	}
+}
+
+// withoutErrors turns a sequence into a sequence of values without errors.
+func withoutErrors[T any](seq iter.Seq[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for v := range seq {
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+// chanSeq yields the values received from ch until it is closed or ctx is done.
+func chanSeq[T any](ctx context.Context, ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-ch:
+				if !ok || !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
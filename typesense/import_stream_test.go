@@ -0,0 +1,195 @@
+package typesense
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+)
+
+// importedLines returns a function recording the JSONL lines of import requests.
+func importedLines(t *testing.T) func(r *http.Request) []string {
+	return func(r *http.Request) []string {
+		validateRequestMetadata(t, r, "/collections/companies/documents/import", http.MethodPost)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		return strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+	}
+}
+
+// newImportTestServer returns a client whose import requests are recorded and
+// answered with a success for every document, except for companies without employees.
+func newImportTestServer(t *testing.T) (*Client, func() [][]string) {
+	return newRecordingTestClient(t, importedLines(t), func(w http.ResponseWriter, r *http.Request, _ int, lines []string) {
+		assert.Equal(t, "upsert", r.URL.Query().Get("action"))
+		for _, line := range lines {
+			if strings.Contains(line, `"num_employees":0`) {
+				fmt.Fprintln(w, `{"success": false, "error": "Field num_employees must be positive.", "document": `+fmt.Sprintf("%q", line)+`}`)
+				continue
+			}
+			fmt.Fprintln(w, `{"success": true}`)
+		}
+	})
+}
+
+func companyDocuments(n int) iter.Seq[companyDocument] {
+	return func(yield func(companyDocument) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(companyDocument{ID: fmt.Sprint(i), CompanyName: "Stark Industries", NumEmployees: i}) {
+				return
+			}
+		}
+	}
+}
+
+func TestTypedDocumentsImportSeqInChunks(t *testing.T) {
+	client, requests := newImportTestServer(t)
+	documents := GenericCollection[companyDocument](client, "companies").TypedDocuments()
+
+	var results []*api.ImportDocumentResponse
+	params := &api.ImportDocumentsParams{Action: pointer.Any(api.Upsert)}
+	for result, err := range documents.ImportSeq(context.Background(), companyDocuments(5), params, WithImportChunkSize(2)) {
+		assert.NoError(t, err)
+		results = append(results, result)
+	}
+
+	assert.Equal(t, []*api.ImportDocumentResponse{
+		{Success: false, Error: "Field num_employees must be positive.", Document: `{"id":"0","company_name":"Stark Industries","num_employees":0}`},
+		{Success: true}, {Success: true}, {Success: true}, {Success: true},
+	}, results)
+	assert.Equal(t, [][]string{
+		{`{"id":"0","company_name":"Stark Industries","num_employees":0}`, `{"id":"1","company_name":"Stark Industries","num_employees":1}`},
+		{`{"id":"2","company_name":"Stark Industries","num_employees":2}`, `{"id":"3","company_name":"Stark Industries","num_employees":3}`},
+		{`{"id":"4","company_name":"Stark Industries","num_employees":4}`},
+	}, requests())
+	assert.Equal(t, pointer.Any(api.Upsert), params.Action, "params are not modified")
+}
+
+func TestTypedDocumentsImportSeqChunkBytes(t *testing.T) {
+	client, requests := newImportTestServer(t)
+	documents := GenericCollection[companyDocument](client, "companies").TypedDocuments()
+
+	// the documents are 63 bytes long including the newline, except for the
+	// second one, which is 77 bytes long
+	seq := func(yield func(companyDocument) bool) {
+		for _, name := range []string{"Stark Industries", "Wayne Enterprises Incorporated", "Acme Corporation", "Initech Software"} {
+			if !yield(companyDocument{ID: "1", CompanyName: name, NumEmployees: 1}) {
+				return
+			}
+		}
+	}
+	params := &api.ImportDocumentsParams{Action: pointer.Any(api.Upsert)}
+	for _, err := range documents.ImportSeq(context.Background(), seq, params, WithImportChunkBytes(130)) {
+		assert.NoError(t, err)
+	}
+
+	var sizes []int
+	for _, request := range requests() {
+		sizes = append(sizes, len(request))
+	}
+	// the second document fits into a chunk neither with the first nor with
+	// the third one
+	assert.Equal(t, []int{1, 1, 2}, sizes)
+}
+
+func TestTypedDocumentsImportChan(t *testing.T) {
+	client, requests := newImportTestServer(t)
+	documents := GenericCollection[companyDocument](client, "companies").TypedDocuments()
+
+	ch := make(chan companyDocument)
+	go func() {
+		defer close(ch)
+		for document := range companyDocuments(4) {
+			ch <- document
+		}
+	}()
+
+	successes := 0
+	params := &api.ImportDocumentsParams{Action: pointer.Any(api.Upsert)}
+	for result, err := range documents.ImportChan(context.Background(), ch, params, WithImportChunkSize(3)) {
+		assert.NoError(t, err)
+		if result.Success {
+			successes++
+		}
+	}
+	assert.Equal(t, 3, successes)
+	assert.Len(t, requests(), 2)
+}
+
+func TestTypedDocumentsImportChanStopsWhenContextIsDone(t *testing.T) {
+	client, requests := newImportTestServer(t)
+	documents := GenericCollection[companyDocument](client, "companies").TypedDocuments()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan companyDocument)
+	cancel()
+
+	var errs []error
+	params := &api.ImportDocumentsParams{Action: pointer.Any(api.Upsert)}
+	for _, err := range documents.ImportChan(ctx, ch, params) {
+		errs = append(errs, err)
+	}
+	assert.Equal(t, []error{context.Canceled}, errs)
+	assert.Empty(t, requests())
+}
+
+func TestTypedDocumentsImportSeqStopsWhenLoopBreaks(t *testing.T) {
+	client, requests := newImportTestServer(t)
+	documents := GenericCollection[companyDocument](client, "companies").TypedDocuments()
+
+	params := &api.ImportDocumentsParams{Action: pointer.Any(api.Upsert)}
+	for _, err := range documents.ImportSeq(context.Background(), companyDocuments(10), params, WithImportChunkSize(2)) {
+		assert.NoError(t, err)
+		break
+	}
+	assert.Len(t, requests(), 1)
+}
+
+func TestTypedDocumentsImportSeqOnHttpStatusErrorCodeReturnsError(t *testing.T) {
+	requests := 0
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"message": "Bad JSON."}`)
+	})
+	defer server.Close()
+	documents := GenericCollection[companyDocument](client, "companies").TypedDocuments()
+
+	var errs []error
+	for result, err := range documents.ImportSeq(context.Background(), companyDocuments(10), &api.ImportDocumentsParams{}, WithImportChunkSize(2)) {
+		assert.Nil(t, result)
+		errs = append(errs, err)
+	}
+	if assert.Len(t, errs, 1) {
+		assert.ErrorIs(t, errs[0], ErrBadRequest)
+	}
+	assert.Equal(t, 1, requests)
+}
+
+func TestTypedDocumentsImportSeqReadsLongResultLines(t *testing.T) {
+	longError := strings.Repeat("x", 128*1024)
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			fmt.Fprintf(w, "{\"success\": false, \"error\": %q}\n", longError)
+		}
+	})
+	defer server.Close()
+	documents := GenericCollection[companyDocument](client, "companies").TypedDocuments()
+
+	results := 0
+	for result, err := range documents.ImportSeq(context.Background(), companyDocuments(2), &api.ImportDocumentsParams{}) {
+		assert.NoError(t, err)
+		assert.Equal(t, longError, result.Error)
+		results++
+	}
+	assert.Equal(t, 2, results)
+}
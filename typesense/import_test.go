@@ -441,3 +441,23 @@ func TestDocumentsImportJsonlWithBatchSizeOnly(t *testing.T) {
 	_, err := client.Collection("companies").Documents().ImportJsonl(context.Background(), importBody, params)
 	assert.Nil(t, err)
 }
+
+func TestDocumentsImportWithResultLargerThanScannerBuffer(t *testing.T) {
+	description := strings.Repeat("a", 100*1024)
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		validateRequestMetadata(t, r, "/collections/companies/documents/import", http.MethodPost)
+		w.Write([]byte(`{"success": true, "document": {"id": "123", "description": "` + description + `"}}` + "\n"))
+		w.Write([]byte(`{"success": true}` + "\n"))
+	})
+	defer server.Close()
+
+	params := &api.ImportDocumentsParams{ReturnDoc: pointer.Any(true)}
+	result, err := client.Collection("companies").Documents().Import(context.Background(),
+		[]interface{}{createNewDocument("123"), createNewDocument("125")}, params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.ImportDocumentResponse{
+		{Success: true, Document: map[string]interface{}{"id": "123", "description": description}},
+		{Success: true},
+	}, result)
+}
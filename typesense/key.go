@@ -22,7 +22,7 @@ func (k *key) Retrieve(ctx context.Context) (*api.ApiKey, error) {
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -33,7 +33,7 @@ func (k *key) Delete(ctx context.Context) (*api.ApiKeyDeleteResponse, error) {
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
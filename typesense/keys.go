@@ -28,7 +28,7 @@ func (k *keys) Create(ctx context.Context, key *api.ApiKeySchema) (*api.ApiKey,
 		return nil, err
 	}
 	if response.JSON201 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON201, nil
 }
@@ -39,7 +39,7 @@ func (k *keys) Retrieve(ctx context.Context) ([]*api.ApiKey, error) {
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200.Keys, nil
 }
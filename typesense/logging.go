@@ -0,0 +1,100 @@
+package typesense
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/sony/gobreaker"
+	"github.com/typesense/typesense-go/v4/typesense/api/circuit"
+)
+
+const apiKeyHeader = "X-TYPESENSE-API-KEY"
+
+const redacted = "REDACTED"
+
+func (a *APICall) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if a.logger == nil || !a.logger.Enabled(ctx, level) {
+		return
+	}
+	a.logger.Log(ctx, level, msg, args...)
+}
+
+// setNodeHealth updates the health of the node after an attempt or a probe and
+// logs whether the node was marked unhealthy or healthy again.
+func (a *APICall) setNodeHealth(ctx context.Context, node *Node, isHealthy bool, response *http.Response, err error) {
+	if node == a.server {
+		// there is no other node to fail over to
+		return
+	}
+	wasHealthy := setNodeHealthCheck(node, isHealthy)
+	switch {
+	case wasHealthy && !isHealthy:
+		a.log(ctx, slog.LevelWarn, "typesense node marked unhealthy",
+			append([]any{slog.String("node", node.url)}, failureLogAttrs(response, err)...)...)
+	case !wasHealthy && isHealthy:
+		a.log(ctx, slog.LevelInfo, "typesense node marked healthy", slog.String("node", node.url))
+	}
+}
+
+// isFailedResponse reports whether the final outcome of a request is worth
+// logging as a failure, i.e. it failed with an error, without a response or
+// with a server side status.
+func isFailedResponse(response *http.Response, err error) bool {
+	return err != nil || response == nil || response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests
+}
+
+func failureLogAttrs(response *http.Response, err error) []any {
+	if err != nil {
+		return []any{slog.Any("error", err)}
+	}
+	if response == nil {
+		return nil
+	}
+	return []any{slog.Int("status", response.StatusCode)}
+}
+
+// requestLogValue logs the method, URL and headers of a request, redacting the API key.
+type requestLogValue struct {
+	req *http.Request
+}
+
+func (v requestLogValue) LogValue() slog.Value {
+	u := *v.req.URL
+	if query := u.Query(); query.Has(apiKeyHeader) || query.Has("x-typesense-api-key") {
+		for key := range query {
+			if http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(apiKeyHeader) {
+				query.Set(key, redacted)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	header := v.req.Header.Clone()
+	if header.Get(apiKeyHeader) != "" {
+		header.Set(apiKeyHeader, redacted)
+	}
+	return slog.GroupValue(
+		slog.String("method", v.req.Method),
+		slog.String("url", u.String()),
+		slog.Any("header", header),
+	)
+}
+
+// logBreakerStateChange logs the state changes of a circuit breaker before
+// calling the onStateChange function configured by the user, if any.
+func logBreakerStateChange(logger *slog.Logger, onStateChange circuit.GoBreakerOnStateChangeFunc) circuit.GoBreakerOnStateChangeFunc {
+	if logger == nil {
+		return onStateChange
+	}
+	return func(name string, from gobreaker.State, to gobreaker.State) {
+		level := slog.LevelInfo
+		if to == gobreaker.StateOpen {
+			level = slog.LevelWarn
+		}
+		logger.Log(context.Background(), level, "typesense circuit breaker state changed",
+			slog.String("breaker", name), slog.String("from", from.String()), slog.String("to", to.String()))
+		if onStateChange != nil {
+			onStateChange(name, from, to)
+		}
+	}
+}
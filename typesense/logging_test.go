@@ -0,0 +1,211 @@
+package typesense
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api/circuit"
+)
+
+func newTestLogger(level slog.Level) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: level})), &buf
+}
+
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestClientLogsRetryAndUnhealthyNode(t *testing.T) {
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(500)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":true}`))
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	logger, buf := newTestLogger(slog.LevelDebug)
+	client := NewClient(
+		WithNodes(serverURLs),
+		WithAPIKey("secret-key"),
+		WithRetryInterval(0),
+		WithLogger(logger),
+	)
+
+	_, err := client.Health(context.Background(), time.Second)
+	assert.NoError(t, err)
+
+	entries := logEntries(t, buf)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "WARN", entries[0]["level"])
+	assert.Equal(t, "typesense node marked unhealthy", entries[0]["msg"])
+	assert.Equal(t, serverURLs[0], entries[0]["node"])
+	assert.Equal(t, float64(500), entries[0]["status"])
+
+	assert.Equal(t, "INFO", entries[1]["level"])
+	assert.Equal(t, "retrying typesense request", entries[1]["msg"])
+	assert.Equal(t, float64(1), entries[1]["attempt"])
+	assert.Equal(t, "status 500", entries[1]["reason"])
+	request := entries[1]["request"].(map[string]any)
+	assert.Equal(t, "GET", request["method"])
+	assert.Equal(t, []any{"REDACTED"}, request["header"].(map[string]any)["X-Typesense-Api-Key"])
+	assert.NotContains(t, buf.String(), "secret-key")
+}
+
+func TestClientLogsFinalFailureAndRecoveredNode(t *testing.T) {
+	defer func() {
+		apiCallTimeNow = time.Now
+	}()
+	var failing bool
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			if failing {
+				w.WriteHeader(503)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":true}`))
+		},
+	})
+	defer servers[0].Close()
+
+	logger, buf := newTestLogger(slog.LevelInfo)
+	client := NewClient(
+		WithNodes(serverURLs),
+		WithAPIKey("secret-key"),
+		WithRetryInterval(0),
+		WithHealthcheckInterval(time.Second),
+		WithLogger(logger),
+	)
+
+	failing = true
+	freezeUnixMilli(0)
+	_, err := client.Health(context.Background(), time.Second)
+	assert.Error(t, err)
+
+	entries := logEntries(t, buf)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "typesense node marked unhealthy", entries[0]["msg"])
+	assert.Equal(t, "ERROR", entries[1]["level"])
+	assert.Equal(t, "typesense request failed", entries[1]["msg"])
+	assert.Equal(t, float64(503), entries[1]["status"])
+	assert.Equal(t, float64(1), entries[1]["attempts"])
+	assert.NotContains(t, buf.String(), "secret-key")
+
+	buf.Reset()
+	failing = false
+	freezeUnixMilli(2000)
+	_, err = client.Health(context.Background(), time.Second)
+	assert.NoError(t, err)
+
+	entries = logEntries(t, buf)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "INFO", entries[0]["level"])
+	assert.Equal(t, "typesense node marked healthy", entries[0]["msg"])
+	assert.Equal(t, serverURLs[0], entries[0]["node"])
+}
+
+func TestClientLogsCircuitBreakerStateChange(t *testing.T) {
+	server, _ := newTestServerAndClient(func(w http.ResponseWriter, _ *http.Request) {
+		// hijack the connection to make the request fail with a transport error
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	})
+	defer server.Close()
+
+	var stateChanges int
+	logger, buf := newTestLogger(slog.LevelInfo)
+	client := NewClient(
+		WithServer(server.URL),
+		WithLogger(logger),
+		WithCircuitBreakerReadyToTrip(func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		}),
+		WithCircuitBreakerOnStateChange(func(string, gobreaker.State, gobreaker.State) {
+			stateChanges++
+		}),
+	)
+
+	_, err := client.Health(context.Background(), time.Second)
+	assert.Error(t, err)
+
+	var breakerEntries []map[string]any
+	for _, entry := range logEntries(t, buf) {
+		if entry["msg"] == "typesense circuit breaker state changed" {
+			breakerEntries = append(breakerEntries, entry)
+		}
+	}
+	assert.Len(t, breakerEntries, 1)
+	assert.Equal(t, "WARN", breakerEntries[0]["level"])
+	assert.Equal(t, "closed", breakerEntries[0]["from"])
+	assert.Equal(t, "open", breakerEntries[0]["to"])
+	assert.Equal(t, 1, stateChanges)
+}
+
+func TestRequestLogValueRedactsAPIKeyQueryParameter(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://localhost:8108/collections?x-typesense-api-key=secret-key", nil)
+	assert.NoError(t, err)
+
+	value := requestLogValue{req}.LogValue()
+	assert.NotContains(t, value.String(), "secret-key")
+	assert.Contains(t, value.String(), "x-typesense-api-key=REDACTED")
+}
+
+type closeCountingBody struct {
+	closed *atomic.Int32
+}
+
+func (b closeCountingBody) Read([]byte) (int, error) { return 0, io.EOF }
+
+func (b closeCountingBody) Close() error {
+	b.closed.Add(1)
+	return nil
+}
+
+func TestAPICallWithStatusZeroResponsesLogsFailureAndClosesBodies(t *testing.T) {
+	var closed atomic.Int32
+	doer := circuit.HTTPRequestDoerFunc(func(*http.Request) (*http.Response, error) {
+		// some clients return status 0 when the request fails
+		return &http.Response{Body: closeCountingBody{&closed}}, nil
+	})
+	logger, buf := newTestLogger(slog.LevelError)
+	apiCall := NewAPICall(doer, &ClientConfig{
+		Nodes:  []string{"http://node-1:8108", "http://node-2:8108"},
+		Logger: logger,
+	})
+
+	response, err := apiCall.Do(newHTTPRequest(t))
+
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+	assert.Equal(t, int32(2), closed.Load())
+	entries := logEntries(t, buf)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "typesense request failed", entries[0]["msg"])
+	assert.Equal(t, float64(2), entries[0]["attempts"])
+}
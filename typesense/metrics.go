@@ -2,12 +2,140 @@ package typesense
 
 import (
 	"context"
+	"regexp"
+	"sort"
+	"strconv"
 )
 
 type MetricsInterface interface {
 	Retrieve(ctx context.Context) (map[string]interface{}, error)
 }
 
+// ServerMetricsInterface is a type for Metrics API operations on typed metrics
+type ServerMetricsInterface interface {
+	MetricsInterface
+	// RetrieveServerMetrics returns the metrics with their values parsed into a ServerMetrics.
+	RetrieveServerMetrics(ctx context.Context) (*ServerMetrics, error)
+}
+
+// ServerMetrics are the system and memory metrics reported by a node at /metrics.json.
+type ServerMetrics struct {
+	// CPUActivePercentage is the CPU usage across all cores.
+	CPUActivePercentage float64
+	// CPUCoresActivePercentage is the CPU usage of every core, in the order of the cores.
+	CPUCoresActivePercentage []float64
+	Memory                   SystemMemoryMetrics
+	Disk                     DiskMetrics
+	Network                  NetworkMetrics
+	TypesenseMemory          TypesenseMemoryMetrics
+	// Other holds the metrics not known to the client, e.g. added by newer server versions.
+	Other map[string]float64
+	// Unparsed holds the metrics whose values could not be parsed as numbers.
+	Unparsed map[string]interface{}
+}
+
+type SystemMemoryMetrics struct {
+	TotalBytes     uint64
+	UsedBytes      uint64
+	TotalSwapBytes uint64
+	UsedSwapBytes  uint64
+}
+
+type DiskMetrics struct {
+	TotalBytes uint64
+	UsedBytes  uint64
+}
+
+// NetworkMetrics are the bytes sent and received by the system since it started.
+type NetworkMetrics struct {
+	ReceivedBytes uint64
+	SentBytes     uint64
+}
+
+// TypesenseMemoryMetrics are the memory allocator statistics of the Typesense process.
+type TypesenseMemoryMetrics struct {
+	ActiveBytes        uint64
+	AllocatedBytes     uint64
+	FragmentationRatio float64
+	MappedBytes        uint64
+	MetadataBytes      uint64
+	ResidentBytes      uint64
+	RetainedBytes      uint64
+}
+
+var cpuCoreMetricPattern = regexp.MustCompile(`^system_cpu(\d+)_active_percentage$`)
+
+var serverMetricFields = map[string]func(m *ServerMetrics, value float64){
+	"system_cpu_active_percentage":         func(m *ServerMetrics, v float64) { m.CPUActivePercentage = v },
+	"system_memory_total_bytes":            func(m *ServerMetrics, v float64) { m.Memory.TotalBytes = uint64(v) },
+	"system_memory_used_bytes":             func(m *ServerMetrics, v float64) { m.Memory.UsedBytes = uint64(v) },
+	"system_memory_total_swap_bytes":       func(m *ServerMetrics, v float64) { m.Memory.TotalSwapBytes = uint64(v) },
+	"system_memory_used_swap_bytes":        func(m *ServerMetrics, v float64) { m.Memory.UsedSwapBytes = uint64(v) },
+	"system_disk_total_bytes":              func(m *ServerMetrics, v float64) { m.Disk.TotalBytes = uint64(v) },
+	"system_disk_used_bytes":               func(m *ServerMetrics, v float64) { m.Disk.UsedBytes = uint64(v) },
+	"system_network_received_bytes":        func(m *ServerMetrics, v float64) { m.Network.ReceivedBytes = uint64(v) },
+	"system_network_sent_bytes":            func(m *ServerMetrics, v float64) { m.Network.SentBytes = uint64(v) },
+	"typesense_memory_active_bytes":        func(m *ServerMetrics, v float64) { m.TypesenseMemory.ActiveBytes = uint64(v) },
+	"typesense_memory_allocated_bytes":     func(m *ServerMetrics, v float64) { m.TypesenseMemory.AllocatedBytes = uint64(v) },
+	"typesense_memory_fragmentation_ratio": func(m *ServerMetrics, v float64) { m.TypesenseMemory.FragmentationRatio = v },
+	"typesense_memory_mapped_bytes":        func(m *ServerMetrics, v float64) { m.TypesenseMemory.MappedBytes = uint64(v) },
+	"typesense_memory_metadata_bytes":      func(m *ServerMetrics, v float64) { m.TypesenseMemory.MetadataBytes = uint64(v) },
+	"typesense_memory_resident_bytes":      func(m *ServerMetrics, v float64) { m.TypesenseMemory.ResidentBytes = uint64(v) },
+	"typesense_memory_retained_bytes":      func(m *ServerMetrics, v float64) { m.TypesenseMemory.RetainedBytes = uint64(v) },
+}
+
+// ParseServerMetrics parses the metrics returned by MetricsInterface.Retrieve,
+// whose values are encoded as strings, e.g. "system_memory_used_bytes": "1004507136".
+// Metrics whose values are not numbers are skipped and kept in Unparsed.
+func ParseServerMetrics(raw map[string]interface{}) *ServerMetrics {
+	metrics := &ServerMetrics{}
+	cores := make(map[int]float64)
+	for name, rawValue := range raw {
+		value, ok := parseMetricValue(rawValue)
+		if !ok {
+			if metrics.Unparsed == nil {
+				metrics.Unparsed = make(map[string]interface{})
+			}
+			metrics.Unparsed[name] = rawValue
+			continue
+		}
+		if setField, ok := serverMetricFields[name]; ok {
+			setField(metrics, value)
+		} else if match := cpuCoreMetricPattern.FindStringSubmatch(name); match != nil {
+			core, _ := strconv.Atoi(match[1])
+			cores[core] = value
+		} else {
+			if metrics.Other == nil {
+				metrics.Other = make(map[string]float64)
+			}
+			metrics.Other[name] = value
+		}
+	}
+
+	// the cores are numbered from 1 and may have more than one digit
+	coreNumbers := make([]int, 0, len(cores))
+	for core := range cores {
+		coreNumbers = append(coreNumbers, core)
+	}
+	sort.Ints(coreNumbers)
+	for _, core := range coreNumbers {
+		metrics.CPUCoresActivePercentage = append(metrics.CPUCoresActivePercentage, cores[core])
+	}
+	return metrics
+}
+
+func parseMetricValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
 type metrics struct {
 	apiClient APIClientInterface
 }
@@ -18,7 +146,15 @@ func (m *metrics) Retrieve(ctx context.Context) (map[string]interface{}, error)
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return *response.JSON200, nil
 }
+
+func (m *metrics) RetrieveServerMetrics(ctx context.Context) (*ServerMetrics, error) {
+	raw, err := m.Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ParseServerMetrics(raw), nil
+}
@@ -55,3 +55,92 @@ func TestMetricsRetrieveOnHttpStatusErrorCodeReturnsError(t *testing.T) {
 	_, err := client.Metrics().Retrieve(context.Background())
 	assert.ErrorContains(t, err, "status: 409")
 }
+
+func TestMetricsRetrieveServerMetrics(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		validateRequestMetadata(t, r, "/metrics.json", http.MethodGet)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"system_cpu1_active_percentage": "10.50",
+			"system_cpu2_active_percentage": "20.00",
+			"system_cpu10_active_percentage": "5.25",
+			"system_cpu_active_percentage": "11.92",
+			"system_disk_total_bytes": "1043447808",
+			"system_disk_used_bytes": "561152",
+			"system_memory_total_bytes": "2086899712",
+			"system_memory_used_bytes": "1004507136",
+			"system_memory_total_swap_bytes": "1004507136",
+			"system_memory_used_swap_bytes": "0.00",
+			"system_network_received_bytes": "1466",
+			"system_network_sent_bytes": "182",
+			"typesense_memory_active_bytes": "29630464",
+			"typesense_memory_allocated_bytes": "27886840",
+			"typesense_memory_fragmentation_ratio": "0.06",
+			"typesense_memory_mapped_bytes": "69701632",
+			"typesense_memory_metadata_bytes": "4588768",
+			"typesense_memory_resident_bytes": "29630464",
+			"typesense_memory_retained_bytes": "25718784",
+			"typesense_new_metric": 42
+		}`))
+	})
+	defer server.Close()
+
+	res, err := client.Metrics().RetrieveServerMetrics(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &ServerMetrics{
+		CPUActivePercentage:      11.92,
+		CPUCoresActivePercentage: []float64{10.5, 20, 5.25},
+		Memory: SystemMemoryMetrics{
+			TotalBytes:     2086899712,
+			UsedBytes:      1004507136,
+			TotalSwapBytes: 1004507136,
+			UsedSwapBytes:  0,
+		},
+		Disk: DiskMetrics{
+			TotalBytes: 1043447808,
+			UsedBytes:  561152,
+		},
+		Network: NetworkMetrics{
+			ReceivedBytes: 1466,
+			SentBytes:     182,
+		},
+		TypesenseMemory: TypesenseMemoryMetrics{
+			ActiveBytes:        29630464,
+			AllocatedBytes:     27886840,
+			FragmentationRatio: 0.06,
+			MappedBytes:        69701632,
+			MetadataBytes:      4588768,
+			ResidentBytes:      29630464,
+			RetainedBytes:      25718784,
+		},
+		Other: map[string]float64{"typesense_new_metric": 42},
+	}, res)
+}
+
+func TestParseServerMetricsSkipsInvalidValues(t *testing.T) {
+	metrics := ParseServerMetrics(map[string]interface{}{
+		"system_cpu_active_percentage":  "11.92",
+		"system_cpu1_active_percentage": "n/a",
+		"system_memory_used_bytes":      "n/a",
+		"typesense_new_metric":          true,
+	})
+	assert.Equal(t, &ServerMetrics{
+		CPUActivePercentage: 11.92,
+		Unparsed: map[string]interface{}{
+			"system_cpu1_active_percentage": "n/a",
+			"system_memory_used_bytes":      "n/a",
+			"typesense_new_metric":          true,
+		},
+	}, metrics)
+}
+
+func TestMetricsRetrieveServerMetricsOnHttpStatusErrorCodeReturnsError(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		validateRequestMetadata(t, r, "/metrics.json", http.MethodGet)
+		w.WriteHeader(http.StatusConflict)
+	})
+	defer server.Close()
+
+	_, err := client.Metrics().RetrieveServerMetrics(context.Background())
+	assert.ErrorIs(t, err, ErrConflict)
+}
@@ -0,0 +1,73 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/typesense/typesense-go/v4/typesense/api/circuit"
+)
+
+// Middleware wraps the HTTPRequestDoer that sends requests, e.g. to add headers,
+// audit requests or inject faults. Use circuit.HTTPRequestDoerFunc to implement
+// it with a function:
+//
+//	func(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+//		return circuit.HTTPRequestDoerFunc(func(req *http.Request) (*http.Response, error) {
+//			req.Header.Set("X-Request-ID", uuid.NewString())
+//			return next.Do(req)
+//		})
+//	}
+type Middleware func(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer
+
+// chainMiddlewares wraps doer with the middlewares, so that the first
+// middleware is the first one to see a request.
+func chainMiddlewares(doer circuit.HTTPRequestDoer, middlewares []Middleware) circuit.HTTPRequestDoer {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		doer = middlewares[i](doer)
+	}
+	return doer
+}
+
+// AttemptInfo describes a single attempt of a request to a node. Attempt
+// middlewares get it from the request context with AttemptInfoFromContext.
+type AttemptInfo struct {
+	// Number is the number of the attempt, starting at 1. A hedged attempt has
+	// the same number as the attempt it hedges.
+	Number int
+	// NodeURL is the URL of the node the attempt is sent to.
+	NodeURL string
+	// Hedged is true if the attempt was sent because the previous one was slow.
+	Hedged bool
+	// RetryReason describes why the previous attempt failed, e.g. "status 503".
+	// It is empty for the first attempt.
+	RetryReason string
+}
+
+type attemptInfoContextKey struct{}
+
+// AttemptInfoFromContext returns the AttemptInfo of the request attempt made with ctx.
+func AttemptInfoFromContext(ctx context.Context) (AttemptInfo, bool) {
+	info, ok := ctx.Value(attemptInfoContextKey{}).(AttemptInfo)
+	return info, ok
+}
+
+func withAttemptInfo(req *http.Request, info AttemptInfo) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), attemptInfoContextKey{}, info))
+}
+
+// describeFailedAttempt returns the retry reason of the attempt after the failed one.
+func describeFailedAttempt(response *http.Response, err error) string {
+	var rejectedErr *nodeBreakerRejectedError
+	switch {
+	case errors.As(err, &rejectedErr):
+		return "circuit breaker open"
+	case err != nil:
+		return "transport error"
+	case isThrottled(response):
+		return "throttled"
+	default:
+		return fmt.Sprintf("status %d", response.StatusCode)
+	}
+}
@@ -0,0 +1,199 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api/circuit"
+)
+
+func passthroughMiddleware(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+	return next
+}
+
+// recordingMiddleware appends name to calls before and after every request.
+func recordingMiddleware(mu *sync.Mutex, calls *[]string, name string) Middleware {
+	return func(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+		return circuit.HTTPRequestDoerFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			*calls = append(*calls, "before "+name)
+			mu.Unlock()
+			res, err := next.Do(req)
+			mu.Lock()
+			*calls = append(*calls, "after "+name)
+			mu.Unlock()
+			return res, err
+		})
+	}
+}
+
+func TestChainMiddlewaresRunsInOrder(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	doer := circuit.HTTPRequestDoerFunc(func(*http.Request) (*http.Response, error) {
+		calls = append(calls, "doer")
+		return &http.Response{StatusCode: 200}, nil
+	})
+
+	chained := chainMiddlewares(doer, []Middleware{
+		recordingMiddleware(&mu, &calls, "first"),
+		recordingMiddleware(&mu, &calls, "second"),
+	})
+	_, err := chained.Do(newHTTPRequest(t))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"before first", "before second", "doer", "after second", "after first"}, calls)
+}
+
+func TestClientMiddlewareAddsHeader(t *testing.T) {
+	server, _ := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "req-1", r.Header.Get("X-Request-ID"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	})
+	defer server.Close()
+
+	client := NewClient(
+		WithServer(server.URL),
+		WithMiddleware(func(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+			return circuit.HTTPRequestDoerFunc(func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Request-ID", "req-1")
+				return next.Do(req)
+			})
+		}),
+	)
+
+	healthy, err := client.Health(context.Background(), time.Second)
+	assert.NoError(t, err)
+	assert.True(t, healthy)
+}
+
+func TestClientMiddlewareRunsPerCallAndAttemptMiddlewarePerAttempt(t *testing.T) {
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(500)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":true}`))
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	var calls, attempts atomic.Int64
+	var attemptHosts []string
+	client := NewClient(
+		WithNodes(serverURLs),
+		WithRetryInterval(0),
+		WithMiddleware(func(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+			return circuit.HTTPRequestDoerFunc(func(req *http.Request) (*http.Response, error) {
+				calls.Add(1)
+				return next.Do(req)
+			})
+		}),
+		WithAttemptMiddleware(func(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+			return circuit.HTTPRequestDoerFunc(func(req *http.Request) (*http.Response, error) {
+				attempts.Add(1)
+				attemptHosts = append(attemptHosts, "http://"+req.URL.Host)
+				return next.Do(req)
+			})
+		}),
+	)
+	defer client.Close()
+
+	healthy, err := client.Health(context.Background(), time.Second)
+	assert.NoError(t, err)
+	assert.True(t, healthy)
+
+	assert.Equal(t, int64(1), calls.Load())
+	assert.Equal(t, int64(2), attempts.Load())
+	assert.Equal(t, serverURLs, attemptHosts)
+}
+
+func TestClientAttemptMiddlewareInjectsFaults(t *testing.T) {
+	var hits atomic.Int64
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			hits.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":true}`))
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			hits.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":true}`))
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	errInjected := errors.New("injected fault")
+	var injected atomic.Bool
+	client := NewClient(
+		WithNodes(serverURLs),
+		WithRetryInterval(0),
+		WithAttemptMiddleware(func(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+			return circuit.HTTPRequestDoerFunc(func(req *http.Request) (*http.Response, error) {
+				// fail the first attempt before it reaches the node
+				if injected.CompareAndSwap(false, true) {
+					return nil, errInjected
+				}
+				return next.Do(req)
+			})
+		}),
+	)
+	defer client.Close()
+
+	healthy, err := client.Health(context.Background(), time.Second)
+	assert.NoError(t, err)
+	assert.True(t, healthy)
+	assert.Equal(t, int64(1), hits.Load())
+	assert.False(t, client.NodeStatus()[0].Healthy)
+}
+
+func TestClientAttemptMiddlewareGetsAttemptInfo(t *testing.T) {
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(503)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":true}`))
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	var infos []AttemptInfo
+	client := NewClient(
+		WithNodes(serverURLs),
+		WithRetryInterval(0),
+		WithAttemptMiddleware(func(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+			return circuit.HTTPRequestDoerFunc(func(req *http.Request) (*http.Response, error) {
+				info, ok := AttemptInfoFromContext(req.Context())
+				assert.True(t, ok)
+				infos = append(infos, info)
+				return next.Do(req)
+			})
+		}),
+	)
+	defer client.Close()
+
+	_, err := client.Health(context.Background(), time.Second)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []AttemptInfo{
+		{Number: 1, NodeURL: serverURLs[0]},
+		{Number: 2, NodeURL: serverURLs[1], RetryReason: "status 503"},
+	}, infos)
+}
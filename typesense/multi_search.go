@@ -32,7 +32,7 @@ func (m *multiSearch) Perform(ctx context.Context, commonSearchParams *api.Multi
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -53,7 +53,7 @@ func (m *multiSearch) PerformWithContentType(ctx context.Context, commonSearchPa
 		return nil, err
 	}
 	if response.Body == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response, nil
 }
@@ -75,7 +75,7 @@ func (m *multiSearch) PerformUnion(ctx context.Context, commonSearchParams *api.
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 
 	// Unmarshal the raw JSON body into SearchResult instead of MultiSearchResult
@@ -103,5 +103,8 @@ func multiSearchTopLevelError(response *api.MultiSearchResponse) error {
 		return nil
 	}
 
-	return &HTTPError{Status: *errorResponse.Code, Body: response.Body}
+	// the status of the response is 200, the actual status is in the body
+	httpErr := newHTTPError(response.HTTPResponse, response.Body)
+	httpErr.Status = *errorResponse.Code
+	return httpErr
 }
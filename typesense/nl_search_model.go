@@ -23,7 +23,7 @@ func (n *nlSearchModel) Retrieve(ctx context.Context) (*api.NLSearchModelSchema,
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -34,7 +34,7 @@ func (n *nlSearchModel) Update(ctx context.Context, model *api.NLSearchModelUpda
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -45,7 +45,7 @@ func (n *nlSearchModel) Delete(ctx context.Context) (*api.NLSearchModelDeleteSch
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -21,7 +21,7 @@ func (n *nlSearchModels) Retrieve(ctx context.Context) ([]*api.NLSearchModelSche
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 
 	// Convert []NLSearchModelSchema to []*NLSearchModelSchema
@@ -39,7 +39,7 @@ func (n *nlSearchModels) Create(ctx context.Context, model *api.NLSearchModelCre
 		return nil, err
 	}
 	if response.JSON201 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON201, nil
 }
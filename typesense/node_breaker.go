@@ -0,0 +1,76 @@
+package typesense
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sony/gobreaker"
+	"github.com/typesense/typesense-go/v4/typesense/api/circuit"
+)
+
+// errNodeServerError makes a node's circuit breaker count a 5xx response as a failure.
+var errNodeServerError = errors.New("server error")
+
+// nodeBreakerRejectedError is returned when the circuit breaker of a node
+// rejected a request without sending it.
+type nodeBreakerRejectedError struct {
+	nodeURL string
+	err     error
+}
+
+func (e *nodeBreakerRejectedError) Error() string {
+	return fmt.Sprintf("circuit breaker of node %s rejected request: %v", e.nodeURL, e.err)
+}
+
+func (e *nodeBreakerRejectedError) Unwrap() error {
+	return e.err
+}
+
+// doWithNodeBreaker sends the request through the circuit breaker of a node.
+// Unlike the client-wide breaker, transport errors as well as 5xx responses
+// count as failures, so that a failing node is isolated from the others.
+func doWithNodeBreaker(breaker circuit.Breaker, client circuit.HTTPRequestDoer, req *http.Request) (*http.Response, error) {
+	var response *http.Response
+	var doErr error
+	sent := false
+	err := breaker.Execute(func() error {
+		sent = true
+		response, doErr = client.Do(req)
+		if doErr == nil && response.StatusCode >= 500 {
+			return errNodeServerError
+		}
+		return doErr
+	})
+	if !sent {
+		return nil, &nodeBreakerRejectedError{nodeURL: req.URL.Host, err: err}
+	}
+	return response, doErr
+}
+
+// breakerOpen reports whether the node has a circuit breaker that currently
+// rejects all requests.
+func (n *Node) breakerOpen() bool {
+	state, ok := breakerState(n.breaker)
+	return ok && state == gobreaker.StateOpen
+}
+
+// breakerState returns the state of a circuit breaker that reports it, like circuit.GoBreaker.
+func breakerState(breaker circuit.Breaker) (gobreaker.State, bool) {
+	stateBreaker, ok := breaker.(interface{ State() gobreaker.State })
+	if !ok {
+		return gobreaker.StateClosed, false
+	}
+	return stateBreaker.State(), true
+}
+
+func newGoBreaker(config *ClientConfig, name string) *circuit.GoBreaker {
+	return circuit.NewGoBreaker(
+		circuit.WithGoBreakerName(name),
+		circuit.WithGoBreakerMaxRequests(config.CircuitBreakerMaxRequests),
+		circuit.WithGoBreakerInterval(config.CircuitBreakerInterval),
+		circuit.WithGoBreakerTimeout(config.CircuitBreakerTimeout),
+		circuit.WithGoBreakerReadyToTrip(config.CircuitBreakerReadyToTrip),
+		circuit.WithGoBreakerOnStateChange(logBreakerStateChange(config.Logger, config.CircuitBreakerOnStateChange)),
+	)
+}
@@ -0,0 +1,123 @@
+package typesense
+
+import (
+	"bytes"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api/circuit"
+)
+
+func newNodeBreakerConfig(nodes []string) *ClientConfig {
+	return &ClientConfig{
+		Nodes:                     nodes,
+		HealthcheckInterval:       10 * time.Millisecond,
+		ConnectionTimeout:         5 * time.Second,
+		CircuitBreakerName:        defaultCircuitBreakerName,
+		CircuitBreakerMaxRequests: circuit.DefaultGoBreakerMaxRequests,
+		CircuitBreakerInterval:    circuit.DefaultGoBreakerInterval,
+		CircuitBreakerTimeout:     time.Minute,
+		CircuitBreakerReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= 2
+		},
+		CircuitBreakerPerNode: true,
+	}
+}
+
+func TestApiCallPerNodeCircuitBreakerIsolatesFailingNode(t *testing.T) {
+	var failingHits, healthyHits atomic.Int64
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			failingHits.Add(1)
+			w.WriteHeader(500)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			healthyHits.Add(1)
+			w.WriteHeader(200)
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+	defer func() {
+		apiCallTimeNow = time.Now
+	}()
+
+	apiCall := newAPICall(newNodeBreakerConfig(serverURLs))
+
+	for i := 0; i < 10; i++ {
+		// the failing node is due for a healthcheck before every request
+		freezeUnixMilli(int64(i) * 100)
+		res, err := apiCall.Do(newHTTPRequest(t))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, res.StatusCode)
+	}
+
+	assert.Equal(t, int64(2), failingHits.Load())
+	assert.Equal(t, int64(10), healthyHits.Load())
+	assert.Equal(t, gobreaker.StateOpen, apiCall.nodes[0].breaker.(*circuit.GoBreaker).State())
+	assert.Equal(t, gobreaker.StateClosed, apiCall.nodes[1].breaker.(*circuit.GoBreaker).State())
+
+	statuses := apiCall.NodeStatus()
+	assert.Equal(t, "open", statuses[0].CircuitBreakerState)
+	assert.Equal(t, "closed", statuses[1].CircuitBreakerState)
+}
+
+func TestApiCallReplaysNonIdempotentRequestRejectedByNodeCircuitBreaker(t *testing.T) {
+	var hits atomic.Int64
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			hits.Add(1)
+			w.WriteHeader(201)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			hits.Add(1)
+			w.WriteHeader(201)
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	apiCall := newAPICall(newNodeBreakerConfig(serverURLs))
+	// a breaker that does not report its state, so the node is still selected
+	apiCall.nodes[0].breaker = &rejectingBreaker{}
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/collections/companies/documents",
+		bytes.NewBuffer([]byte(`{"id":"123"}`)))
+	assert.NoError(t, err)
+
+	res, err := apiCall.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 201, res.StatusCode)
+	assert.Equal(t, int64(1), hits.Load())
+}
+
+type rejectingBreaker struct{}
+
+func (b *rejectingBreaker) Execute(func() error) error {
+	return gobreaker.ErrOpenState
+}
+
+func TestClientWithCircuitBreakerPerNodeDoesNotUseClientWideBreaker(t *testing.T) {
+	client := NewClient(
+		WithNodes([]string{"http://localhost:8108", "http://localhost:8109"}),
+		WithCircuitBreakerPerNode(true),
+	)
+	apiClient := getAPIClient(t, client.apiClient)
+	assert.Same(t, client.apiCall, apiClient.Client)
+	for _, node := range client.apiCall.nodes {
+		assert.NotNil(t, node.breaker)
+	}
+
+	client = NewClient(WithNodes([]string{"http://localhost:8108"}))
+	apiClient = getAPIClient(t, client.apiClient)
+	assert.IsType(t, &circuit.HTTPClient{}, apiClient.Client)
+	assert.Nil(t, client.apiCall.nodes[0].breaker)
+}
@@ -0,0 +1,196 @@
+package typesense
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NodeSelector picks the node each request attempt is sent to. The nearest node,
+// if configured, takes precedence over the selector as long as it is healthy.
+// Implementations must be safe for concurrent use.
+type NodeSelector interface {
+	// Select returns the node for the next request attempt. It should prefer
+	// available nodes, but must return one of the nodes even if none is available.
+	Select(nodes []*Node) *Node
+	// Observe is called after every attempt made to a node with the time it
+	// took to receive the response headers and the transport error, if any.
+	Observe(node *Node, latency time.Duration, err error)
+}
+
+// URL returns the URL of the node.
+func (n *Node) URL() string {
+	return n.url
+}
+
+// Available reports whether the node is healthy or has been unhealthy for
+// longer than the healthcheck interval and can be tried again. A node whose
+// circuit breaker is open is never available.
+func (n *Node) Available() bool {
+	if n.breakerOpen() {
+		return false
+	}
+	return n.isHealthy.Load() || apiCallTimeNow().UnixMilli()-n.lastAccessTimestamp.Load() > n.healthcheckInterval.Milliseconds()
+}
+
+// Outstanding returns the number of requests currently in flight to the node.
+func (n *Node) Outstanding() int64 {
+	return n.outstanding.Load()
+}
+
+// RoundRobinSelector cycles through the available nodes in order.
+// It is the default NodeSelector.
+type RoundRobinSelector struct {
+	currentNodeIndex atomic.Int64
+}
+
+var _ NodeSelector = (*RoundRobinSelector)(nil)
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	s := &RoundRobinSelector{}
+	s.currentNodeIndex.Store(-1)
+	return s
+}
+
+func (s *RoundRobinSelector) Select(nodes []*Node) *Node {
+	candidateNode := nodes[0]
+	for i := 0; i <= len(nodes); i++ {
+		// every caller advances the shared cursor, so concurrent requests
+		// are spread across the nodes instead of racing for the same one
+		candidateNode = nodes[s.next(len(nodes))]
+		if candidateNode.Available() {
+			return candidateNode
+		}
+	}
+	// None of the nodes are marked healthy, but some of them could have become healthy since last health check.
+	// So we will just return the next node.
+	return candidateNode
+}
+
+func (s *RoundRobinSelector) Observe(*Node, time.Duration, error) {}
+
+func (s *RoundRobinSelector) next(numNodes int) int {
+	return int(s.currentNodeIndex.Add(1) % int64(numNodes))
+}
+
+// selectMinCost returns the available node with the lowest cost. Ties are broken
+// in round-robin order, so that nodes with equal cost share the load.
+func selectMinCost(nodes []*Node, roundRobin *RoundRobinSelector, cost func(*Node) float64) *Node {
+	offset := roundRobin.next(len(nodes))
+	var bestNode *Node
+	bestCost := math.Inf(1)
+	for i := range nodes {
+		node := nodes[(offset+i)%len(nodes)]
+		if !node.Available() {
+			continue
+		}
+		if c := cost(node); bestNode == nil || c < bestCost {
+			bestNode, bestCost = node, c
+		}
+	}
+	if bestNode == nil {
+		return nodes[offset]
+	}
+	return bestNode
+}
+
+// LeastOutstandingSelector sends each attempt to the available node with the
+// fewest requests in flight.
+type LeastOutstandingSelector struct {
+	roundRobin *RoundRobinSelector
+}
+
+var _ NodeSelector = (*LeastOutstandingSelector)(nil)
+
+func NewLeastOutstandingSelector() *LeastOutstandingSelector {
+	return &LeastOutstandingSelector{roundRobin: NewRoundRobinSelector()}
+}
+
+func (s *LeastOutstandingSelector) Select(nodes []*Node) *Node {
+	return selectMinCost(nodes, s.roundRobin, func(node *Node) float64 {
+		return float64(node.Outstanding())
+	})
+}
+
+func (s *LeastOutstandingSelector) Observe(*Node, time.Duration, error) {}
+
+const DefaultPeakEWMADecay = 10 * time.Second
+
+// PeakEWMASelector sends each attempt to the available node with the lowest
+// expected latency, estimated as the peak exponentially weighted moving average
+// of its latency multiplied by the number of requests in flight plus one.
+// Latency spikes are taken into account immediately and decay over time, so
+// slow nodes are avoided quickly and retried once they have recovered.
+type PeakEWMASelector struct {
+	decay      time.Duration
+	roundRobin *RoundRobinSelector
+	latencies  sync.Map // *Node -> *peakEWMA
+}
+
+var _ NodeSelector = (*PeakEWMASelector)(nil)
+
+// NewPeakEWMASelector returns a PeakEWMASelector whose latency estimates decay
+// with the given time constant. If decay is 0, DefaultPeakEWMADecay is used.
+func NewPeakEWMASelector(decay time.Duration) *PeakEWMASelector {
+	if decay <= 0 {
+		decay = DefaultPeakEWMADecay
+	}
+	return &PeakEWMASelector{decay: decay, roundRobin: NewRoundRobinSelector()}
+}
+
+func (s *PeakEWMASelector) Select(nodes []*Node) *Node {
+	now := time.Now()
+	return selectMinCost(nodes, s.roundRobin, func(node *Node) float64 {
+		// nodes without any observation have no cost, so every node is tried
+		latency := 0.0
+		if value, ok := s.latencies.Load(node); ok {
+			latency = value.(*peakEWMA).value(now, s.decay)
+		}
+		return latency * float64(node.Outstanding()+1)
+	})
+}
+
+func (s *PeakEWMASelector) Observe(node *Node, latency time.Duration, _ error) {
+	value, _ := s.latencies.LoadOrStore(node, &peakEWMA{})
+	value.(*peakEWMA).observe(time.Now(), latency, s.decay)
+}
+
+// Latency returns the current latency estimate of the node.
+func (s *PeakEWMASelector) Latency(node *Node) time.Duration {
+	value, ok := s.latencies.Load(node)
+	if !ok {
+		return 0
+	}
+	return time.Duration(value.(*peakEWMA).value(time.Now(), s.decay))
+}
+
+type peakEWMA struct {
+	mu       sync.Mutex
+	estimate float64
+	updated  time.Time
+}
+
+func (e *peakEWMA) observe(now time.Time, latency time.Duration, decay time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rtt := float64(latency)
+	if rtt > e.estimate || e.updated.IsZero() {
+		e.estimate = rtt
+	} else {
+		weight := math.Exp(-float64(now.Sub(e.updated)) / float64(decay))
+		e.estimate = e.estimate*weight + rtt*(1-weight)
+	}
+	e.updated = now
+}
+
+func (e *peakEWMA) value(now time.Time, decay time.Duration) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.updated.IsZero() {
+		return 0
+	}
+	// the estimate decays towards zero while the node is not observed, so
+	// that a node that was slow once is eventually tried again
+	return e.estimate * math.Exp(-float64(now.Sub(e.updated))/float64(decay))
+}
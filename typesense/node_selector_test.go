@@ -0,0 +1,135 @@
+package typesense
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestNodes(urls ...string) []*Node {
+	nodes := make([]*Node, 0, len(urls))
+	for i, url := range urls {
+		node := &Node{index: i, url: url, healthcheckInterval: time.Minute}
+		setNodeHealthCheck(node, HEALTHY)
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func selectURLs(selector NodeSelector, nodes []*Node, n int) []string {
+	urls := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		urls = append(urls, selector.Select(nodes).URL())
+	}
+	return urls
+}
+
+func TestRoundRobinSelectorSkipsUnavailableNodes(t *testing.T) {
+	nodes := newTestNodes("a", "b", "c")
+	selector := NewRoundRobinSelector()
+
+	assert.Equal(t, []string{"a", "b", "c", "a"}, selectURLs(selector, nodes, 4))
+
+	setNodeHealthCheck(nodes[1], UNHEALTHY)
+	assert.Equal(t, []string{"c", "a", "c"}, selectURLs(selector, nodes, 3))
+
+	for _, node := range nodes {
+		setNodeHealthCheck(node, UNHEALTHY)
+	}
+	// falls back to the next node when none is available
+	assert.NotNil(t, selector.Select(nodes))
+}
+
+func TestLeastOutstandingSelectorPrefersIdleNodes(t *testing.T) {
+	nodes := newTestNodes("a", "b", "c")
+	selector := NewLeastOutstandingSelector()
+
+	nodes[0].outstanding.Store(3)
+	nodes[1].outstanding.Store(1)
+	nodes[2].outstanding.Store(2)
+	assert.Equal(t, []string{"b", "b"}, selectURLs(selector, nodes, 2))
+
+	setNodeHealthCheck(nodes[1], UNHEALTHY)
+	assert.Equal(t, "c", selector.Select(nodes).URL())
+
+	// ties are broken in round-robin order
+	for _, node := range nodes {
+		node.outstanding.Store(0)
+		setNodeHealthCheck(node, HEALTHY)
+	}
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, selectURLs(selector, nodes, 3))
+}
+
+func TestPeakEWMASelectorPrefersFastestNode(t *testing.T) {
+	nodes := newTestNodes("a", "b", "c")
+	selector := NewPeakEWMASelector(0)
+
+	selector.Observe(nodes[0], 50*time.Millisecond, nil)
+	selector.Observe(nodes[1], 5*time.Millisecond, nil)
+	selector.Observe(nodes[2], 20*time.Millisecond, nil)
+	assert.Equal(t, []string{"b", "b", "b"}, selectURLs(selector, nodes, 3))
+
+	// a latency spike is taken into account immediately
+	selector.Observe(nodes[1], 100*time.Millisecond, nil)
+	assert.Equal(t, "c", selector.Select(nodes).URL())
+
+	// requests in flight increase the expected latency
+	nodes[2].outstanding.Store(5)
+	assert.Equal(t, "a", selector.Select(nodes).URL())
+
+	// unhealthy nodes are skipped
+	setNodeHealthCheck(nodes[0], UNHEALTHY)
+	assert.Equal(t, "b", selector.Select(nodes).URL())
+}
+
+func TestPeakEWMASelectorDecaysLatency(t *testing.T) {
+	nodes := newTestNodes("a")
+	selector := NewPeakEWMASelector(10 * time.Millisecond)
+
+	selector.Observe(nodes[0], time.Second, errors.New("timeout"))
+	assert.InDelta(t, float64(time.Second), float64(selector.Latency(nodes[0])), float64(100*time.Millisecond))
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Less(t, selector.Latency(nodes[0]), 100*time.Millisecond)
+}
+
+func TestApiCallWithPeakEWMASelectorGravitatesToFastestNode(t *testing.T) {
+	var slowHits, fastHits atomic.Int64
+
+	servers, serverURLs := instantiateServers([]serverHandler{
+		func(w http.ResponseWriter, _ *http.Request) {
+			slowHits.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			w.WriteHeader(200)
+		},
+		func(w http.ResponseWriter, _ *http.Request) {
+			fastHits.Add(1)
+			w.WriteHeader(200)
+		},
+	})
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	apiCall := newAPICall(
+		&ClientConfig{
+			Nodes:               serverURLs,
+			NodeSelector:        NewPeakEWMASelector(time.Minute),
+			HealthcheckInterval: time.Minute,
+			ConnectionTimeout:   5 * time.Second,
+		},
+	)
+
+	for i := 0; i < 20; i++ {
+		res, err := apiCall.Do(newHTTPRequest(t))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, res.StatusCode)
+	}
+
+	assert.LessOrEqual(t, slowHits.Load(), int64(2))
+	assert.GreaterOrEqual(t, fastHits.Load(), int64(18))
+}
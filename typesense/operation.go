@@ -0,0 +1,115 @@
+package typesense
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Operation identifies the API operation of a request, e.g. to name spans or metrics.
+type Operation struct {
+	// Name is the name of the operation, e.g. "documents.search" or "collection.retrieve".
+	Name string
+	// Collection is the name of the collection the operation targets, if any.
+	Collection string
+	// Known reports whether Name consists only of resources and actions of the
+	// API, and hence holds no ids, which is the case unless the path is unknown.
+	Known bool
+}
+
+// singularResources maps the resources whose path may be followed by an id to
+// the name of a single item, e.g. /keys/{id} is a "key".
+var singularResources = map[string]string{
+	"collections":      "collection",
+	"documents":        "document",
+	"overrides":        "override",
+	"synonyms":         "synonym",
+	"aliases":          "alias",
+	"keys":             "key",
+	"presets":          "preset",
+	"stopwords":        "stopwords",
+	"synonym_sets":     "synonym_set",
+	"curation_sets":    "curation_set",
+	"items":            "item",
+	"rules":            "rule",
+	"models":           "model",
+	"nl_search_models": "nl_search_model",
+	"dictionaries":     "dictionary",
+}
+
+// pathSegments are the other path segments of the API, which never are ids.
+var pathSegments = map[string]bool{
+	"analytics": true, "events": true, "flush": true, "status": true,
+	"config": true, "conversations": true, "debug": true, "health": true,
+	"metrics": true, "stats": true, "multi_search": true, "stemming": true,
+	"operations": true, "cache": true, "clear": true, "db": true, "compact": true,
+	"schema_changes": true, "snapshot": true, "vote": true,
+}
+
+// resourceActions are path segments that name an action on the preceding resource.
+var resourceActions = map[string]bool{
+	"search": true,
+	"import": true,
+	"export": true,
+}
+
+// RequestOperation returns the operation of a request sent to the API. Items
+// are named after their resource, e.g. GET /collections/{name} is
+// "collection.retrieve", and the operations on a collection are named without
+// it, e.g. GET /collections/{name}/documents/search is "documents.search".
+func RequestOperation(req *http.Request) Operation {
+	var operation Operation
+	segments := requestPathSegments(req)
+	if len(segments) == 1 && segments[0] == "" {
+		return operation
+	}
+
+	names := make([]string, 0, len(segments))
+	isResource := false
+	operation.Known = true
+	for i := 0; i < len(segments); i++ {
+		segment := strings.TrimSuffix(segments[i], ".json")
+		singular, ok := singularResources[segment]
+		hasID := ok && i+1 < len(segments) && !resourceActions[segments[i+1]]
+		switch {
+		case hasID && segment == "collections" && i == 0:
+			operation.Collection = segments[i+1]
+			if i+2 == len(segments) {
+				names = append(names, singular)
+			}
+			i++
+		case hasID:
+			names = append(names, singular)
+			i++
+		default:
+			names = append(names, segment)
+			operation.Known = operation.Known && (ok || resourceActions[segment] || pathSegments[segment])
+		}
+		isResource = ok
+	}
+	if isResource {
+		names = append(names, operationVerb(req))
+	}
+	operation.Name = strings.Join(names, ".")
+	return operation
+}
+
+func operationVerb(req *http.Request) string {
+	switch req.Method {
+	case http.MethodGet:
+		return "retrieve"
+	case http.MethodPost:
+		// documents are indexed with an action, e.g. POST /collections/{name}/documents?action=upsert
+		if action := req.URL.Query().Get("action"); action != "" {
+			return action
+		}
+		return "create"
+	case http.MethodPut:
+		return "upsert"
+	case http.MethodPatch:
+		return "update"
+	case http.MethodDelete:
+		return "delete"
+	default:
+		return strings.ToLower(req.Method)
+	}
+}
@@ -0,0 +1,100 @@
+package typesense
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestOperation(t *testing.T) {
+	tests := []struct {
+		method    string
+		path      string
+		operation Operation
+	}{
+		{method: http.MethodGet, path: "/collections", operation: Operation{Name: "collections.retrieve", Known: true}},
+		{method: http.MethodPost, path: "/collections", operation: Operation{Name: "collections.create", Known: true}},
+		{method: http.MethodGet, path: "/collections/books", operation: Operation{Name: "collection.retrieve", Collection: "books", Known: true}},
+		{method: http.MethodPatch, path: "/collections/books", operation: Operation{Name: "collection.update", Collection: "books", Known: true}},
+		{method: http.MethodDelete, path: "/collections/books", operation: Operation{Name: "collection.delete", Collection: "books", Known: true}},
+		{method: http.MethodPost, path: "/collections/books/documents", operation: Operation{Name: "documents.create", Collection: "books", Known: true}},
+		{method: http.MethodPost, path: "/collections/books/documents?action=upsert", operation: Operation{Name: "documents.upsert", Collection: "books", Known: true}},
+		{method: http.MethodDelete, path: "/collections/books/documents?filter_by=num:>1", operation: Operation{Name: "documents.delete", Collection: "books", Known: true}},
+		{method: http.MethodGet, path: "/collections/books/documents/search?q=a", operation: Operation{Name: "documents.search", Collection: "books", Known: true}},
+		{method: http.MethodPost, path: "/collections/books/documents/import?action=create", operation: Operation{Name: "documents.import", Collection: "books", Known: true}},
+		{method: http.MethodGet, path: "/collections/books/documents/export", operation: Operation{Name: "documents.export", Collection: "books", Known: true}},
+		{method: http.MethodGet, path: "/collections/books/documents/123", operation: Operation{Name: "document.retrieve", Collection: "books", Known: true}},
+		{method: http.MethodPatch, path: "/collections/books/documents/123", operation: Operation{Name: "document.update", Collection: "books", Known: true}},
+		{method: http.MethodGet, path: "/collections/books/overrides", operation: Operation{Name: "overrides.retrieve", Collection: "books", Known: true}},
+		{method: http.MethodPut, path: "/collections/books/overrides/promote", operation: Operation{Name: "override.upsert", Collection: "books", Known: true}},
+		{method: http.MethodPost, path: "/multi_search", operation: Operation{Name: "multi_search", Known: true}},
+		{method: http.MethodPut, path: "/aliases/books", operation: Operation{Name: "alias.upsert", Known: true}},
+		{method: http.MethodDelete, path: "/keys/1", operation: Operation{Name: "key.delete", Known: true}},
+		{method: http.MethodGet, path: "/health", operation: Operation{Name: "health", Known: true}},
+		{method: http.MethodGet, path: "/metrics.json", operation: Operation{Name: "metrics", Known: true}},
+		{method: http.MethodPost, path: "/operations/snapshot?snapshot_path=/tmp", operation: Operation{Name: "operations.snapshot", Known: true}},
+		{method: http.MethodPut, path: "/analytics/rules/popular", operation: Operation{Name: "analytics.rule.upsert", Known: true}},
+		{method: http.MethodPost, path: "/analytics/events", operation: Operation{Name: "analytics.events", Known: true}},
+		{method: http.MethodPost, path: "/conversations/models", operation: Operation{Name: "conversations.models.create", Known: true}},
+		{method: http.MethodPost, path: "/stemming/dictionaries/import?id=plurals", operation: Operation{Name: "stemming.dictionaries.import", Known: true}},
+		{method: http.MethodGet, path: "/synonym_sets/products", operation: Operation{Name: "synonym_set.retrieve", Known: true}},
+		{method: http.MethodGet, path: "/synonym_sets/products/items", operation: Operation{Name: "synonym_set.items.retrieve", Known: true}},
+		{method: http.MethodPut, path: "/synonym_sets/products/items/i9", operation: Operation{Name: "synonym_set.item.upsert", Known: true}},
+		{method: http.MethodGet, path: "/curation_sets", operation: Operation{Name: "curation_sets.retrieve", Known: true}},
+		{method: http.MethodDelete, path: "/curation_sets/my-set", operation: Operation{Name: "curation_set.delete", Known: true}},
+		{method: http.MethodGet, path: "/curation_sets/my-set/items", operation: Operation{Name: "curation_set.items.retrieve", Known: true}},
+		{method: http.MethodGet, path: "/curation_sets/my-set/items/abc", operation: Operation{Name: "curation_set.item.retrieve", Known: true}},
+		{method: http.MethodGet, path: "/unknown/123", operation: Operation{Name: "unknown.123"}},
+		{method: http.MethodGet, path: "/", operation: Operation{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "http://localhost:8108"+tt.path, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.operation, RequestOperation(req))
+		})
+	}
+}
+
+// apiPaths are the paths of the operations of the API, with %s for the ids.
+var apiPaths = []string{
+	"/aliases", "/aliases/%s",
+	"/analytics/events", "/analytics/flush", "/analytics/rules", "/analytics/rules/%s", "/analytics/status",
+	"/collections", "/collections/%s",
+	"/collections/%s/documents", "/collections/%s/documents/%s",
+	"/collections/%s/documents/export", "/collections/%s/documents/import", "/collections/%s/documents/search",
+	"/collections/%s/overrides", "/collections/%s/overrides/%s",
+	"/collections/%s/synonyms", "/collections/%s/synonyms/%s",
+	"/config",
+	"/conversations/models", "/conversations/models/%s",
+	"/curation_sets", "/curation_sets/%s", "/curation_sets/%s/items", "/curation_sets/%s/items/%s",
+	"/debug", "/health",
+	"/keys", "/keys/%s",
+	"/metrics.json", "/multi_search",
+	"/nl_search_models", "/nl_search_models/%s",
+	"/operations/cache/clear", "/operations/db/compact", "/operations/schema_changes", "/operations/snapshot", "/operations/vote",
+	"/presets", "/presets/%s",
+	"/stats.json",
+	"/stemming/dictionaries", "/stemming/dictionaries/%s", "/stemming/dictionaries/import",
+	"/stopwords", "/stopwords/%s",
+	"/synonym_sets", "/synonym_sets/%s", "/synonym_sets/%s/items", "/synonym_sets/%s/items/%s",
+}
+
+func TestRequestOperationNamesHaveNoIDs(t *testing.T) {
+	for _, path := range apiPaths {
+		t.Run(path, func(t *testing.T) {
+			ids := make([]any, strings.Count(path, "%s"))
+			for i := range ids {
+				ids[i] = fmt.Sprintf("id-%d", i)
+			}
+			req, err := http.NewRequest(http.MethodGet, "http://localhost:8108"+fmt.Sprintf(path, ids...), nil)
+			assert.NoError(t, err)
+			operation := RequestOperation(req)
+			assert.True(t, operation.Known)
+			assert.NotContains(t, operation.Name, "id-")
+		})
+	}
+}
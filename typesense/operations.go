@@ -22,7 +22,7 @@ func (o *operations) Snapshot(ctx context.Context, snapshotPath string) (bool, e
 		return false, err
 	}
 	if response.JSON201 == nil {
-		return false, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return false, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON201.Success, nil
 }
@@ -33,7 +33,7 @@ func (o *operations) Vote(ctx context.Context) (bool, error) {
 		return false, err
 	}
 	if response.JSON200 == nil {
-		return false, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return false, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200.Success, nil
 }
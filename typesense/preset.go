@@ -22,7 +22,7 @@ func (p *preset) Retrieve(ctx context.Context) (*api.PresetSchema, error) {
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -33,7 +33,7 @@ func (p *preset) Delete(ctx context.Context) (*api.PresetDeleteSchema, error) {
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -21,7 +21,7 @@ func (p *presets) Retrieve(ctx context.Context) ([]*api.PresetSchema, error) {
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200.Presets, nil
 }
@@ -32,7 +32,7 @@ func (p *presets) Upsert(ctx context.Context, presetName string, presetValue *ap
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -0,0 +1,221 @@
+// Package promcollector exposes client-side metrics of a typesense.Client to Prometheus.
+//
+//	collector := promcollector.New()
+//	client := typesense.NewClient(
+//		typesense.WithNodes(nodes),
+//		typesense.WithAPIKey("<API_KEY>"),
+//		collector.Instrument(),
+//	)
+//	prometheus.MustRegister(collector)
+package promcollector
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/typesense/typesense-go/v4/typesense"
+	"github.com/typesense/typesense-go/v4/typesense/api/circuit"
+)
+
+const namespace = "typesense_client"
+
+// Collector is a prometheus.Collector recording the requests made by a client,
+// the attempts sent to its nodes and the health of the nodes and circuit breakers.
+type Collector struct {
+	client atomic.Pointer[typesense.Client]
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	retries         *prometheus.CounterVec
+	attemptDuration *prometheus.HistogramVec
+
+	nodeHealthy        *prometheus.Desc
+	breakerState       *prometheus.Desc
+	throttledResponses *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+type config struct {
+	buckets       []float64
+	constLabels   prometheus.Labels
+	scrapeTimeout time.Duration
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{buckets: prometheus.DefBuckets, scrapeTimeout: defaultScrapeTimeout}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Option configures a Collector.
+type Option func(*config)
+
+// WithBuckets sets the buckets of the latency histograms, in seconds.
+// By default, prometheus.DefBuckets is used.
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// WithConstLabels adds labels with fixed values to all metrics,
+// e.g. to tell apart the metrics of several clients.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *config) {
+		c.constLabels = labels
+	}
+}
+
+// New returns a Collector. Use Instrument to record the metrics of a client.
+func New(opts ...Option) *Collector {
+	c := newConfig(opts)
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "requests_total",
+			Help:        "Number of requests made by the client by operation and status code, or \"error\" for transport errors.",
+			ConstLabels: c.constLabels,
+		}, []string{"operation", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Latency of the requests made by the client by operation, including retries.",
+			Buckets:     c.buckets,
+			ConstLabels: c.constLabels,
+		}, []string{"operation"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "retries_total",
+			Help:        "Number of retried and hedged attempts by operation and reason.",
+			ConstLabels: c.constLabels,
+		}, []string{"operation", "reason"}),
+		attemptDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "attempt_duration_seconds",
+			Help:        "Latency of the attempts sent to each node until the response headers were received.",
+			Buckets:     c.buckets,
+			ConstLabels: c.constLabels,
+		}, []string{"node"}),
+		nodeHealthy: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "node_healthy"),
+			"Whether the node is considered healthy by the client (1) or not (0).",
+			[]string{"node"}, c.constLabels),
+		breakerState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "circuit_breaker_state"),
+			"State of the circuit breaker of the client (breaker=\"client\") or of a node: 0 closed, 1 half-open, 2 open.",
+			[]string{"breaker"}, c.constLabels),
+		throttledResponses: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "throttled_responses_total"),
+			"Number of 429 responses and 503 responses with a Retry-After header.",
+			nil, c.constLabels),
+	}
+}
+
+// Instrument returns the client option that records the metrics of the client.
+// A Collector records the metrics of a single client.
+func (c *Collector) Instrument() typesense.ClientOption {
+	return func(client *typesense.Client) {
+		c.client.Store(client)
+		typesense.WithMiddleware(c.requestMiddleware)(client)
+		typesense.WithAttemptMiddleware(c.attemptMiddleware)(client)
+	}
+}
+
+func (c *Collector) requestMiddleware(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+	return circuit.HTTPRequestDoerFunc(func(req *http.Request) (*http.Response, error) {
+		operation := operationLabel(req)
+		start := time.Now()
+		response, err := next.Do(req)
+		c.requestDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+		status := "error"
+		if err == nil {
+			status = strconv.Itoa(response.StatusCode)
+		}
+		c.requests.WithLabelValues(operation, status).Inc()
+		return response, err
+	})
+}
+
+func (c *Collector) attemptMiddleware(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+	return circuit.HTTPRequestDoerFunc(func(req *http.Request) (*http.Response, error) {
+		info, ok := typesense.AttemptInfoFromContext(req.Context())
+		if !ok {
+			// background health probes are reported by the node health
+			return next.Do(req)
+		}
+		switch {
+		case info.Hedged:
+			c.retries.WithLabelValues(operationLabel(req), "hedge").Inc()
+		case info.RetryReason != "":
+			c.retries.WithLabelValues(operationLabel(req), info.RetryReason).Inc()
+		}
+		start := time.Now()
+		response, err := next.Do(req)
+		c.attemptDuration.WithLabelValues(info.NodeURL).Observe(time.Since(start).Seconds())
+		return response, err
+	})
+}
+
+// operationLabel returns the name of the operation of the request, or "other"
+// if the name may hold ids, which would make the number of series unbounded.
+func operationLabel(req *http.Request) string {
+	if operation := typesense.RequestOperation(req); operation.Known {
+		return operation.Name
+	}
+	return "other"
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.requestDuration.Describe(ch)
+	c.retries.Describe(ch)
+	c.attemptDuration.Describe(ch)
+	ch <- c.nodeHealthy
+	ch <- c.breakerState
+	ch <- c.throttledResponses
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.requestDuration.Collect(ch)
+	c.retries.Collect(ch)
+	c.attemptDuration.Collect(ch)
+
+	client := c.client.Load()
+	if client == nil {
+		return
+	}
+	for _, status := range client.NodeStatus() {
+		healthy := 0.0
+		if status.Healthy {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.nodeHealthy, prometheus.GaugeValue, healthy, status.URL)
+		if status.CircuitBreakerState != "" {
+			ch <- prometheus.MustNewConstMetric(c.breakerState, prometheus.GaugeValue,
+				breakerStateValue(status.CircuitBreakerState), status.URL)
+		}
+	}
+	if state := client.CircuitBreakerState(); state != "" {
+		ch <- prometheus.MustNewConstMetric(c.breakerState, prometheus.GaugeValue, breakerStateValue(state), "client")
+	}
+	ch <- prometheus.MustNewConstMetric(c.throttledResponses, prometheus.CounterValue,
+		float64(client.ThrottlingStats().ThrottledResponses))
+}
+
+func breakerStateValue(state string) float64 {
+	switch state {
+	case "half-open":
+		return 1
+	case "open":
+		return 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,120 @@
+package promcollector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sony/gobreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense"
+	"github.com/typesense/typesense-go/v4/typesense/api/circuit"
+)
+
+func TestCollectorRecordsRequestsAndRetries(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer healthy.Close()
+
+	collector := New()
+	client := typesense.NewClient(
+		typesense.WithNodes([]string{failing.URL, healthy.URL}),
+		typesense.WithRetryInterval(0),
+		typesense.WithHealthcheckInterval(time.Minute),
+		collector.Instrument(),
+	)
+	defer client.Close()
+
+	registry := prometheus.NewPedanticRegistry()
+	assert.NoError(t, registry.Register(collector))
+
+	_, err := client.Health(context.Background(), time.Second)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.requests.WithLabelValues("health", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.retries.WithLabelValues("health", "status 503")))
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "typesense_client_request_duration_seconds"))
+	assert.Equal(t, 2, testutil.CollectAndCount(collector, "typesense_client_attempt_duration_seconds"))
+
+	expected := `
+# HELP typesense_client_node_healthy Whether the node is considered healthy by the client (1) or not (0).
+# TYPE typesense_client_node_healthy gauge
+typesense_client_node_healthy{node="` + failing.URL + `"} 0
+typesense_client_node_healthy{node="` + healthy.URL + `"} 1
+# HELP typesense_client_circuit_breaker_state State of the circuit breaker of the client (breaker="client") or of a node: 0 closed, 1 half-open, 2 open.
+# TYPE typesense_client_circuit_breaker_state gauge
+typesense_client_circuit_breaker_state{breaker="client"} 0
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"typesense_client_node_healthy", "typesense_client_circuit_breaker_state"))
+}
+
+func TestCollectorRecordsTransportErrorsAndNodeBreakers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// hijack the connection to make every request fail with a transport error
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer server.Close()
+
+	collector := New(WithConstLabels(prometheus.Labels{"client": "search"}))
+	client := typesense.NewClient(
+		typesense.WithNodes([]string{server.URL}),
+		typesense.WithCircuitBreakerPerNode(true),
+		typesense.WithCircuitBreakerReadyToTrip(func(gobreaker.Counts) bool {
+			return true
+		}),
+		collector.Instrument(),
+	)
+	defer client.Close()
+
+	_, err := client.Collections().Retrieve(context.Background(), nil)
+	assert.Error(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.requests.WithLabelValues("collections.retrieve", "error")))
+
+	registry := prometheus.NewPedanticRegistry()
+	assert.NoError(t, registry.Register(collector))
+	expected := `
+# HELP typesense_client_circuit_breaker_state State of the circuit breaker of the client (breaker="client") or of a node: 0 closed, 1 half-open, 2 open.
+# TYPE typesense_client_circuit_breaker_state gauge
+typesense_client_circuit_breaker_state{breaker="` + server.URL + `",client="search"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"typesense_client_circuit_breaker_state"))
+}
+
+func TestCollectorOperationLabelsHoldNoIDs(t *testing.T) {
+	collector := New()
+	ok := circuit.HTTPRequestDoerFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	paths := []string{
+		"/curation_sets/my-set/items/a", "/curation_sets/my-set/items/b", "/curation_sets/other/items/c",
+		"/synonym_sets/x/items/i1", "/synonym_sets/y/items/i2",
+		"/unknown/1", "/unknown/2",
+	}
+	for _, path := range paths {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost:8108"+path, nil)
+		assert.NoError(t, err)
+		_, err = collector.requestMiddleware(ok).Do(req)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, testutil.CollectAndCount(collector.requests))
+	assert.Equal(t, 3.0, testutil.ToFloat64(collector.requests.WithLabelValues("curation_set.item.retrieve", "200")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(collector.requests.WithLabelValues("synonym_set.item.retrieve", "200")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(collector.requests.WithLabelValues("other", "200")))
+	assert.Equal(t, 3, testutil.CollectAndCount(collector.requestDuration))
+}
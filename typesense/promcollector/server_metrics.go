@@ -0,0 +1,127 @@
+package promcollector
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/typesense/typesense-go/v4/typesense"
+)
+
+const serverNamespace = "typesense_server"
+
+const defaultScrapeTimeout = 10 * time.Second
+
+// WithScrapeTimeout sets how long a ServerMetricsCollector waits for the
+// metrics of the server on every scrape. The default is 10 seconds.
+func WithScrapeTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.scrapeTimeout = timeout
+	}
+}
+
+type serverMetric struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	value     func(m *typesense.ServerMetrics) float64
+}
+
+// ServerMetricsCollector is a prometheus.Collector re-exposing the metrics of
+// the server, which are retrieved from /metrics.json on every scrape.
+type ServerMetricsCollector struct {
+	metrics typesense.ServerMetricsInterface
+	timeout time.Duration
+
+	up      *prometheus.Desc
+	cpuCore *prometheus.Desc
+	values  []serverMetric
+}
+
+var _ prometheus.Collector = (*ServerMetricsCollector)(nil)
+
+// Export registers a ServerMetricsCollector for the metrics of the client, e.g.
+//
+//	promcollector.Export(client.Metrics(), prometheus.DefaultRegisterer)
+func Export(metrics typesense.ServerMetricsInterface, registerer prometheus.Registerer, opts ...Option) error {
+	return registerer.Register(NewServerMetricsCollector(metrics, opts...))
+}
+
+func NewServerMetricsCollector(metrics typesense.ServerMetricsInterface, opts ...Option) *ServerMetricsCollector {
+	c := newConfig(opts)
+	newDesc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(serverNamespace, "", name), help, labels, c.constLabels)
+	}
+	gauge := func(name, help string, value func(m *typesense.ServerMetrics) float64) serverMetric {
+		return serverMetric{desc: newDesc(name, help), valueType: prometheus.GaugeValue, value: value}
+	}
+	counter := func(name, help string, value func(m *typesense.ServerMetrics) float64) serverMetric {
+		return serverMetric{desc: newDesc(name, help), valueType: prometheus.CounterValue, value: value}
+	}
+	return &ServerMetricsCollector{
+		metrics: metrics,
+		timeout: c.scrapeTimeout,
+		up:      newDesc("up", "Whether the metrics of the server could be retrieved (1) or not (0)."),
+		cpuCore: newDesc("cpu_core_active_percentage", "CPU usage of a core of the server.", "core"),
+		values: []serverMetric{
+			gauge("cpu_active_percentage", "CPU usage of the server across all cores.",
+				func(m *typesense.ServerMetrics) float64 { return m.CPUActivePercentage }),
+			gauge("memory_total_bytes", "Total memory of the server.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.Memory.TotalBytes) }),
+			gauge("memory_used_bytes", "Used memory of the server.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.Memory.UsedBytes) }),
+			gauge("memory_swap_total_bytes", "Total swap of the server.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.Memory.TotalSwapBytes) }),
+			gauge("memory_swap_used_bytes", "Used swap of the server.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.Memory.UsedSwapBytes) }),
+			gauge("disk_total_bytes", "Total disk space of the server.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.Disk.TotalBytes) }),
+			gauge("disk_used_bytes", "Used disk space of the server.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.Disk.UsedBytes) }),
+			counter("network_received_bytes_total", "Bytes received by the server.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.Network.ReceivedBytes) }),
+			counter("network_sent_bytes_total", "Bytes sent by the server.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.Network.SentBytes) }),
+			gauge("typesense_memory_active_bytes", "Memory in active pages of the Typesense process.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.TypesenseMemory.ActiveBytes) }),
+			gauge("typesense_memory_allocated_bytes", "Memory allocated by the Typesense process.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.TypesenseMemory.AllocatedBytes) }),
+			gauge("typesense_memory_fragmentation_ratio", "Memory fragmentation of the Typesense process.",
+				func(m *typesense.ServerMetrics) float64 { return m.TypesenseMemory.FragmentationRatio }),
+			gauge("typesense_memory_mapped_bytes", "Memory mapped by the Typesense process.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.TypesenseMemory.MappedBytes) }),
+			gauge("typesense_memory_metadata_bytes", "Memory used for allocator metadata by the Typesense process.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.TypesenseMemory.MetadataBytes) }),
+			gauge("typesense_memory_resident_bytes", "Resident memory of the Typesense process.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.TypesenseMemory.ResidentBytes) }),
+			gauge("typesense_memory_retained_bytes", "Memory retained by the allocator of the Typesense process.",
+				func(m *typesense.ServerMetrics) float64 { return float64(m.TypesenseMemory.RetainedBytes) }),
+		},
+	}
+}
+
+func (c *ServerMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.up
+	ch <- c.cpuCore
+	for _, value := range c.values {
+		ch <- value.desc
+	}
+}
+
+func (c *ServerMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	metrics, err := c.metrics.RetrieveServerMetrics(ctx)
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 0)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 1)
+	for i, percentage := range metrics.CPUCoresActivePercentage {
+		ch <- prometheus.MustNewConstMetric(c.cpuCore, prometheus.GaugeValue, percentage, strconv.Itoa(i+1))
+	}
+	for _, value := range c.values {
+		ch <- prometheus.MustNewConstMetric(value.desc, value.valueType, value.value(metrics))
+	}
+}
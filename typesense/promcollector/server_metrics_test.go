@@ -0,0 +1,80 @@
+package promcollector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense"
+)
+
+func TestExportServerMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/metrics.json", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"system_cpu1_active_percentage": "10.50",
+			"system_cpu2_active_percentage": "20.00",
+			"system_cpu_active_percentage": "15.25",
+			"system_memory_used_bytes": "1004507136",
+			"system_network_sent_bytes": "182",
+			"typesense_memory_fragmentation_ratio": "0.06"
+		}`))
+	}))
+	defer server.Close()
+
+	client := typesense.NewClient(typesense.WithServer(server.URL))
+	registry := prometheus.NewPedanticRegistry()
+	assert.NoError(t, Export(client.Metrics(), registry))
+
+	expected := `
+# HELP typesense_server_up Whether the metrics of the server could be retrieved (1) or not (0).
+# TYPE typesense_server_up gauge
+typesense_server_up 1
+# HELP typesense_server_cpu_active_percentage CPU usage of the server across all cores.
+# TYPE typesense_server_cpu_active_percentage gauge
+typesense_server_cpu_active_percentage 15.25
+# HELP typesense_server_cpu_core_active_percentage CPU usage of a core of the server.
+# TYPE typesense_server_cpu_core_active_percentage gauge
+typesense_server_cpu_core_active_percentage{core="1"} 10.5
+typesense_server_cpu_core_active_percentage{core="2"} 20
+# HELP typesense_server_memory_used_bytes Used memory of the server.
+# TYPE typesense_server_memory_used_bytes gauge
+typesense_server_memory_used_bytes 1.004507136e+09
+# HELP typesense_server_network_sent_bytes_total Bytes sent by the server.
+# TYPE typesense_server_network_sent_bytes_total counter
+typesense_server_network_sent_bytes_total 182
+# HELP typesense_server_typesense_memory_fragmentation_ratio Memory fragmentation of the Typesense process.
+# TYPE typesense_server_typesense_memory_fragmentation_ratio gauge
+typesense_server_typesense_memory_fragmentation_ratio 0.06
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"typesense_server_up",
+		"typesense_server_cpu_active_percentage",
+		"typesense_server_cpu_core_active_percentage",
+		"typesense_server_memory_used_bytes",
+		"typesense_server_network_sent_bytes_total",
+		"typesense_server_typesense_memory_fragmentation_ratio"))
+	assert.Equal(t, 19, testutil.CollectAndCount(registry))
+}
+
+func TestServerMetricsCollectorReportsDownServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := typesense.NewClient(typesense.WithServer(server.URL))
+	collector := NewServerMetricsCollector(client.Metrics())
+
+	expected := `
+# HELP typesense_server_up Whether the metrics of the server could be retrieved (1) or not (0).
+# TYPE typesense_server_up gauge
+typesense_server_up 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
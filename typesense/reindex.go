@@ -0,0 +1,319 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/typesense/typesense-go/v4/typesense/api"
+)
+
+// ReindexOption configures Client.Reindex.
+type ReindexOption func(*reindexConfig)
+
+type reindexConfig struct {
+	sourceCollection string
+	documents        iter.Seq2[any, error]
+	retainedVersions int
+	chunkSize        int
+	exportParams     *api.ExportDocumentsParams
+	importParams     *api.ImportDocumentsParams
+}
+
+// WithReindexSourceCollection exports the documents from the given collection
+// instead of the collection the alias points to.
+func WithReindexSourceCollection(collectionName string) ReindexOption {
+	return func(c *reindexConfig) {
+		c.sourceCollection = collectionName
+	}
+}
+
+// WithReindexDocuments indexes the documents yielded by documents instead of
+// exporting them from a collection. Reindex fails with the first error yielded.
+func WithReindexDocuments(documents iter.Seq2[any, error]) ReindexOption {
+	return func(c *reindexConfig) {
+		c.documents = documents
+	}
+}
+
+// WithReindexRetainedVersions drops all but the n most recent previous versions
+// of the collection once the alias points to the new version. The version the
+// alias pointed to before is always retained and counts towards n, so it can be
+// rolled back to even if newer versions exist. By default no version is dropped.
+func WithReindexRetainedVersions(n int) ReindexOption {
+	return func(c *reindexConfig) {
+		c.retainedVersions = n
+	}
+}
+
+// WithReindexChunkSize sets the number of documents sent per import request.
+func WithReindexChunkSize(n int) ReindexOption {
+	return func(c *reindexConfig) {
+		c.chunkSize = n
+	}
+}
+
+// WithReindexExportParams sets the parameters used to export the documents of
+// the source collection, e.g. to exclude embeddings that should be regenerated.
+func WithReindexExportParams(params *api.ExportDocumentsParams) ReindexOption {
+	return func(c *reindexConfig) {
+		c.exportParams = params
+	}
+}
+
+// WithReindexImportParams sets the parameters used to import the documents into
+// the new collection.
+func WithReindexImportParams(params *api.ImportDocumentsParams) ReindexOption {
+	return func(c *reindexConfig) {
+		c.importParams = params
+	}
+}
+
+// ReindexResult describes a completed reindex.
+type ReindexResult struct {
+	// Collection is the new version of the collection, e.g. products_v7.
+	Collection string
+	// PreviousCollection is the collection the alias pointed to before, if any.
+	PreviousCollection string
+	// Documents is the number of documents indexed into Collection.
+	Documents int64
+	// DroppedCollections are the previous versions dropped because of
+	// WithReindexRetainedVersions.
+	DroppedCollections []string
+}
+
+// ReindexError is returned by Client.Reindex if documents could not be imported.
+type ReindexError struct {
+	Collection string
+	// Failed is the number of documents that could not be imported, and
+	// Errors the import errors of the first of them.
+	Failed int
+	Errors []string
+}
+
+func (e *ReindexError) Error() string {
+	return fmt.Sprintf("failed to import %d documents into %s: %v", e.Failed, e.Collection, e.Errors)
+}
+
+const maxReindexErrors = 10
+
+// Reindex indexes the documents of a collection into a new version of it without
+// downtime. It creates the collection <alias>_v<N> with the given schema, where N
+// is one more than the highest existing version, indexes the documents of the
+// collection the alias points to into it, verifies that the new collection has
+// as many documents as the source and then points the alias to it:
+//
+//	result, err := client.Reindex(ctx, "products", schema,
+//		typesense.WithReindexRetainedVersions(2))
+//
+// The name of the schema is ignored. Documents written to the previous
+// collection while Reindex runs are not copied, so writes should be paused or
+// replayed afterwards. When exporting from a collection without a filter_by
+// export parameter, the new collection must have the number of documents the
+// source had before the export; otherwise it must have every document imported. If the documents cannot be indexed, the new collection is
+// dropped and the alias is left untouched. Dropping old versions happens after
+// the alias has been updated, so an error doing so is returned together with
+// the result.
+func (c *Client) Reindex(ctx context.Context, alias string, schema *api.CollectionSchema, opts ...ReindexOption) (*ReindexResult, error) {
+	config := &reindexConfig{retainedVersions: -1, chunkSize: defaultImportChunkSize}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.chunkSize <= 0 {
+		config.chunkSize = defaultImportChunkSize
+	}
+
+	result := &ReindexResult{}
+	current, err := c.Alias(alias).Retrieve(ctx)
+	switch {
+	case err == nil:
+		result.PreviousCollection = current.CollectionName
+	case !errors.Is(err, ErrNotFound):
+		return nil, err
+	}
+
+	existing, err := c.Collections().Retrieve(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	versions := collectionVersions(alias, existing)
+	nextVersion := 1
+	if len(versions) > 0 {
+		nextVersion = versions[len(versions)-1].version + 1
+	}
+	result.Collection = fmt.Sprintf("%s_v%d", alias, nextVersion)
+
+	newSchema := *schema
+	newSchema.Name = result.Collection
+	if _, err := c.Collections().Create(ctx, &newSchema); err != nil {
+		return nil, err
+	}
+
+	var expected int64
+	result.Documents, expected, err = c.copyDocuments(ctx, result.Collection, result.PreviousCollection, config)
+	if err == nil {
+		err = c.verifyDocumentCount(ctx, result.Collection, expected)
+	}
+	if err == nil {
+		_, err = c.Aliases().Upsert(ctx, alias, &api.CollectionAliasSchema{CollectionName: result.Collection})
+	}
+	if err != nil {
+		// the alias still points to the previous collection, so the new one is
+		// dropped even if ctx is done
+		if _, dropErr := c.Collection(result.Collection).Delete(context.WithoutCancel(ctx)); dropErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to drop collection %s: %w", result.Collection, dropErr))
+		}
+		return nil, err
+	}
+
+	if config.retainedVersions >= 0 {
+		result.DroppedCollections, err = c.dropCollectionVersions(ctx, droppedVersions(versions, result.PreviousCollection, config.retainedVersions))
+	}
+	return result, err
+}
+
+// droppedVersions returns the versions to drop to retain the given number of
+// them, ordered from the oldest to the newest. The previous collection is always
+// retained and the newest of the other versions fill the remaining places.
+func droppedVersions(versions []collectionVersion, previousCollection string, retained int) []collectionVersion {
+	if slices.ContainsFunc(versions, func(v collectionVersion) bool { return v.name == previousCollection }) {
+		retained--
+	}
+	var dropped []collectionVersion
+	for i := len(versions) - 1; i >= 0; i-- {
+		switch {
+		case versions[i].name == previousCollection:
+		case retained > 0:
+			retained--
+		default:
+			dropped = append(dropped, versions[i])
+		}
+	}
+	slices.Reverse(dropped)
+	return dropped
+}
+
+// dropCollectionVersions drops the versions and returns the names of the dropped ones.
+func (c *Client) dropCollectionVersions(ctx context.Context, versions []collectionVersion) ([]string, error) {
+	var dropped []string
+	for _, v := range versions {
+		if _, err := c.Collection(v.name).Delete(ctx); err != nil {
+			return dropped, fmt.Errorf("failed to drop previous version %s: %w", v.name, err)
+		}
+		dropped = append(dropped, v.name)
+	}
+	return dropped, nil
+}
+
+type collectionVersion struct {
+	name    string
+	version int
+}
+
+// collectionVersions returns the versions of the collection named after alias,
+// ordered from the oldest to the newest.
+func collectionVersions(alias string, collections []*api.CollectionResponse) []collectionVersion {
+	pattern := regexp.MustCompile("^" + regexp.QuoteMeta(alias) + `_v(\d+)$`)
+	var versions []collectionVersion
+	for _, collection := range collections {
+		match := pattern.FindStringSubmatch(collection.Name)
+		if match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		versions = append(versions, collectionVersion{name: collection.Name, version: version})
+	}
+	slices.SortFunc(versions, func(a, b collectionVersion) int {
+		return a.version - b.version
+	})
+	return versions
+}
+
+// copyDocuments imports the documents of the source into the collection in
+// chunks. It returns the number of documents imported and the number of
+// documents the collection is expected to have: the number of documents of the
+// source collection before the export if the export is unfiltered, otherwise
+// the number of documents imported.
+func (c *Client) copyDocuments(ctx context.Context, collectionName string, previousCollection string, config *reindexConfig) (imported int64, expected int64, err error) {
+	expected = -1
+	source := config.documents
+	if source == nil {
+		sourceCollection := config.sourceCollection
+		if sourceCollection == "" {
+			sourceCollection = previousCollection
+		}
+		if sourceCollection == "" {
+			return 0, 0, nil
+		}
+		if config.exportParams == nil || config.exportParams.FilterBy == nil {
+			collection, err := c.Collection(sourceCollection).Retrieve(ctx)
+			if err != nil {
+				return 0, 0, err
+			}
+			expected = 0
+			if collection.NumDocuments != nil {
+				expected = *collection.NumDocuments
+			}
+		}
+		source = c.exportedDocuments(ctx, sourceCollection, config.exportParams)
+	}
+
+	reindexErr := &ReindexError{Collection: collectionName}
+	chunking := &importConfig{chunkSize: config.chunkSize, chunkBytes: defaultImportChunkBytes}
+	results := importSeq(ctx, &documents{apiClient: c.apiClient, collectionName: collectionName}, source, config.importParams, chunking)
+	for result, err := range results {
+		if err != nil {
+			return imported, expected, err
+		}
+		if result.Success {
+			imported++
+			continue
+		}
+		reindexErr.Failed++
+		if len(reindexErr.Errors) < maxReindexErrors {
+			reindexErr.Errors = append(reindexErr.Errors, result.Error)
+		}
+	}
+	if reindexErr.Failed > 0 {
+		return imported, expected, reindexErr
+	}
+	if expected < 0 {
+		expected = imported
+	}
+	return imported, expected, nil
+}
+
+// exportedDocuments yields the documents of the collection as json.RawMessage.
+func (c *Client) exportedDocuments(ctx context.Context, collectionName string, params *api.ExportDocumentsParams) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		exported := ExportSeq[json.RawMessage](ctx, &documents{apiClient: c.apiClient, collectionName: collectionName}, params)
+		for document, err := range exported {
+			if !yield(document, err) {
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) verifyDocumentCount(ctx context.Context, collectionName string, expected int64) error {
+	collection, err := c.Collection(collectionName).Retrieve(ctx)
+	if err != nil {
+		return err
+	}
+	var actual int64
+	if collection.NumDocuments != nil {
+		actual = *collection.NumDocuments
+	}
+	if actual != expected {
+		return fmt.Errorf("collection %s has %d documents instead of %d", collectionName, actual, expected)
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package typesense
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+)
+
+// reindexTestServer is an in-memory Typesense server that supports the
+// aliases, collections and document import and export endpoints.
+type reindexTestServer struct {
+	mu          sync.Mutex
+	aliases     map[string]string
+	collections map[string][]json.RawMessage
+	imports     int
+	// lostDocuments are not counted in num_documents of imported collections.
+	lostDocuments int64
+	// truncatedExports is the number of documents left out of each export.
+	truncatedExports int
+}
+
+func newReindexTestServer() *reindexTestServer {
+	return &reindexTestServer{aliases: map[string]string{}, collections: map[string][]json.RawMessage{}}
+}
+
+func (s *reindexTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case segments[0] == "aliases" && r.Method == http.MethodGet:
+		collectionName, ok := s.aliases[segments[1]]
+		if !ok {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, api.CollectionAlias{Name: &segments[1], CollectionName: collectionName})
+	case segments[0] == "aliases" && r.Method == http.MethodPut:
+		var schema api.CollectionAliasSchema
+		json.NewDecoder(r.Body).Decode(&schema)
+		s.aliases[segments[1]] = schema.CollectionName
+		writeJSON(w, api.CollectionAlias{Name: &segments[1], CollectionName: schema.CollectionName})
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		collections := []api.CollectionResponse{}
+		for name := range s.collections {
+			collections = append(collections, api.CollectionResponse{Name: name, Fields: []api.Field{}})
+		}
+		sort.Slice(collections, func(i, j int) bool { return collections[i].Name < collections[j].Name })
+		writeJSON(w, collections)
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		var schema api.CollectionSchema
+		json.NewDecoder(r.Body).Decode(&schema)
+		s.collections[schema.Name] = []json.RawMessage{}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(api.CollectionResponse{Name: schema.Name, Fields: schema.Fields})
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		documents := s.collections[segments[1]]
+		numDocuments := int64(len(documents))
+		if numDocuments > 0 {
+			numDocuments -= s.lostDocuments
+		}
+		writeJSON(w, api.CollectionResponse{Name: segments[1], Fields: []api.Field{}, NumDocuments: &numDocuments})
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		delete(s.collections, segments[1])
+		writeJSON(w, api.CollectionResponse{Name: segments[1], Fields: []api.Field{}})
+	case len(segments) == 4 && segments[3] == "export":
+		documents := s.collections[segments[1]]
+		for _, document := range documents[:max(len(documents)-s.truncatedExports, 0)] {
+			w.Write(document)
+			w.Write([]byte("\n"))
+		}
+	case len(segments) == 4 && segments[3] == "import":
+		s.imports++
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var document struct {
+				Fail bool `json:"fail"`
+			}
+			json.Unmarshal(scanner.Bytes(), &document)
+			if document.Fail {
+				w.Write([]byte(`{"success": false, "error": "Bad document."}` + "\n"))
+				continue
+			}
+			s.collections[segments[1]] = append(s.collections[segments[1]], json.RawMessage(scanner.Text()))
+			w.Write([]byte(`{"success": true}` + "\n"))
+		}
+	default:
+		http.Error(w, fmt.Sprintf("unexpected request %s %s", r.Method, r.URL.Path), http.StatusBadRequest)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *reindexTestServer) collectionNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := []string{}
+	for name := range s.collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func reindexTestDocuments(n int) func(yield func(any, error) bool) {
+	return func(yield func(any, error) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(map[string]any{"id": fmt.Sprint(i), "title": fmt.Sprintf("Product %d", i)}, nil) {
+				return
+			}
+		}
+	}
+}
+
+func newReindexTestClient(t *testing.T, s *reindexTestServer) *Client {
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return NewClient(WithServer(server.URL))
+}
+
+func TestReindexCreatesFirstVersionFromDocuments(t *testing.T) {
+	s := newReindexTestServer()
+	client := newReindexTestClient(t, s)
+
+	result, err := client.Reindex(context.Background(), "products", createNewSchema("ignored"),
+		WithReindexDocuments(reindexTestDocuments(5)), WithReindexChunkSize(2))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ReindexResult{Collection: "products_v1", Documents: 5}, result)
+	assert.Equal(t, "products_v1", s.aliases["products"])
+	assert.Len(t, s.collections["products_v1"], 5)
+	assert.Equal(t, 3, s.imports)
+}
+
+func TestReindexCopiesAliasedCollectionAndDropsOldVersions(t *testing.T) {
+	s := newReindexTestServer()
+	s.collections["products_v1"] = []json.RawMessage{}
+	s.collections["products_v2"] = []json.RawMessage{}
+	s.collections["products_v10"] = []json.RawMessage{
+		json.RawMessage(`{"id":"1","title":"Shoe"}`),
+		json.RawMessage(`{"id":"2","title":"Sock"}`),
+	}
+	s.collections["products_archive"] = []json.RawMessage{}
+	s.aliases["products"] = "products_v10"
+	client := newReindexTestClient(t, s)
+
+	result, err := client.Reindex(context.Background(), "products", createNewSchema("products"),
+		WithReindexRetainedVersions(1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ReindexResult{
+		Collection:         "products_v11",
+		PreviousCollection: "products_v10",
+		Documents:          2,
+		DroppedCollections: []string{"products_v1", "products_v2"},
+	}, result)
+	assert.Equal(t, "products_v11", s.aliases["products"])
+	assert.Equal(t, s.collections["products_v10"], s.collections["products_v11"])
+	assert.Equal(t, []string{"products_archive", "products_v10", "products_v11"}, s.collectionNames())
+}
+
+func TestReindexRetainsPreviousCollectionAfterRollback(t *testing.T) {
+	s := newReindexTestServer()
+	s.collections["products_v4"] = []json.RawMessage{json.RawMessage(`{"id":"1","title":"Shoe"}`)}
+	s.collections["products_v5"] = []json.RawMessage{}
+	s.collections["products_v6"] = []json.RawMessage{}
+	s.aliases["products"] = "products_v4"
+	client := newReindexTestClient(t, s)
+
+	result, err := client.Reindex(context.Background(), "products", createNewSchema("products"),
+		WithReindexRetainedVersions(1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ReindexResult{
+		Collection:         "products_v7",
+		PreviousCollection: "products_v4",
+		Documents:          1,
+		DroppedCollections: []string{"products_v5", "products_v6"},
+	}, result)
+	assert.Equal(t, []string{"products_v4", "products_v7"}, s.collectionNames())
+}
+
+func TestReindexFromSourceCollection(t *testing.T) {
+	s := newReindexTestServer()
+	s.collections["products"] = []json.RawMessage{json.RawMessage(`{"id":"1","title":"Shoe"}`)}
+	client := newReindexTestClient(t, s)
+
+	result, err := client.Reindex(context.Background(), "catalog", createNewSchema("catalog"),
+		WithReindexSourceCollection("products"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ReindexResult{Collection: "catalog_v1", Documents: 1}, result)
+	assert.Equal(t, "catalog_v1", s.aliases["catalog"])
+}
+
+func TestReindexOnImportFailureDropsNewCollection(t *testing.T) {
+	s := newReindexTestServer()
+	s.collections["products_v1"] = []json.RawMessage{}
+	s.aliases["products"] = "products_v1"
+	client := newReindexTestClient(t, s)
+
+	documents := func(yield func(any, error) bool) {
+		yield(map[string]any{"id": "1"}, nil)
+		yield(map[string]any{"id": "2", "fail": true}, nil)
+	}
+	_, err := client.Reindex(context.Background(), "products", createNewSchema("products"),
+		WithReindexDocuments(documents))
+
+	var reindexErr *ReindexError
+	if assert.True(t, errors.As(err, &reindexErr)) {
+		assert.Equal(t, &ReindexError{Collection: "products_v2", Failed: 1, Errors: []string{"Bad document."}}, reindexErr)
+	}
+	assert.Equal(t, "products_v1", s.aliases["products"])
+	assert.Equal(t, []string{"products_v1"}, s.collectionNames())
+}
+
+func TestReindexOnDocumentCountMismatchReturnsError(t *testing.T) {
+	s := newReindexTestServer()
+	s.lostDocuments = 1
+	client := newReindexTestClient(t, s)
+
+	_, err := client.Reindex(context.Background(), "products", createNewSchema("products"),
+		WithReindexDocuments(reindexTestDocuments(3)))
+
+	assert.EqualError(t, err, "collection products_v1 has 2 documents instead of 3")
+	assert.Empty(t, s.aliases)
+	assert.Empty(t, s.collectionNames())
+}
+
+func TestReindexOnShortExportReturnsError(t *testing.T) {
+	s := newReindexTestServer()
+	s.collections["products_v1"] = []json.RawMessage{
+		json.RawMessage(`{"id":"1","title":"Shoe"}`),
+		json.RawMessage(`{"id":"2","title":"Sock"}`),
+		json.RawMessage(`{"id":"3","title":"Hat"}`),
+	}
+	s.aliases["products"] = "products_v1"
+	s.truncatedExports = 1
+	client := newReindexTestClient(t, s)
+
+	_, err := client.Reindex(context.Background(), "products", createNewSchema("products"))
+
+	assert.EqualError(t, err, "collection products_v2 has 2 documents instead of 3")
+	assert.Equal(t, "products_v1", s.aliases["products"])
+	assert.Equal(t, []string{"products_v1"}, s.collectionNames())
+}
+
+func TestReindexOnSourceErrorReturnsError(t *testing.T) {
+	s := newReindexTestServer()
+	client := newReindexTestClient(t, s)
+
+	sourceErr := errors.New("database unavailable")
+	documents := func(yield func(any, error) bool) {
+		if yield(map[string]any{"id": "1"}, nil) {
+			yield(nil, sourceErr)
+		}
+	}
+	_, err := client.Reindex(context.Background(), "products", createNewSchema("products"),
+		WithReindexDocuments(documents))
+
+	assert.ErrorIs(t, err, sourceErr)
+	assert.Empty(t, s.aliases)
+	assert.Empty(t, s.collectionNames())
+}
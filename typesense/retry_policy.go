@@ -0,0 +1,213 @@
+package typesense
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryAttempt describes the outcome of a failed request attempt that is
+// passed to a RetryPolicy.
+type RetryAttempt struct {
+	// Attempt is the number of attempts made so far, starting at 1.
+	Attempt int
+	// Elapsed is the time since the first attempt was started.
+	Elapsed time.Duration
+	// Response is the response of the failed attempt. It is nil when Err is set.
+	Response *http.Response
+	// Err is the transport error of the failed attempt, if any.
+	Err error
+}
+
+// RetryPolicy decides whether a failed request attempt is retried on the next
+// node and how long to wait before doing so.
+type RetryPolicy interface {
+	// NextRetry returns the wait time before the next attempt and whether the
+	// request should be retried at all.
+	NextRetry(attempt *RetryAttempt) (time.Duration, bool)
+}
+
+var retryJitterInt64N = rand.Int64N // for test stubbing
+
+// isRetryableAttempt reports whether an attempt failed with a transport error,
+// a 5xx status or was throttled with a 429 status, consulting statusRules first
+// for the response status code.
+func isRetryableAttempt(attempt *RetryAttempt, statusRules map[int]bool) bool {
+	if attempt.Err != nil || attempt.Response == nil {
+		return true
+	}
+	if retry, ok := statusRules[attempt.Response.StatusCode]; ok {
+		return retry
+	}
+	return attempt.Response.StatusCode >= 500 || attempt.Response.StatusCode == http.StatusTooManyRequests
+}
+
+// FixedIntervalRetryPolicy waits the same amount of time before every retry.
+// It is the default policy, built from ClientConfig.RetryInterval.
+type FixedIntervalRetryPolicy struct {
+	Interval time.Duration
+	// StatusRules overrides whether a response with the given status code is
+	// retried. By default 5xx and 429 responses are retried.
+	StatusRules map[int]bool
+}
+
+var _ RetryPolicy = (*FixedIntervalRetryPolicy)(nil)
+
+func (p *FixedIntervalRetryPolicy) NextRetry(attempt *RetryAttempt) (time.Duration, bool) {
+	if !isRetryableAttempt(attempt, p.StatusRules) {
+		return 0, false
+	}
+	return p.Interval, true
+}
+
+const (
+	DefaultBackoffInitialInterval = 100 * time.Millisecond
+	DefaultBackoffMaxInterval     = 10 * time.Second
+	DefaultBackoffMultiplier      = 2.0
+)
+
+// ExponentialBackoffRetryPolicy grows the wait time exponentially with every
+// attempt and applies full jitter, i.e. the actual wait is a random duration
+// between zero and the computed backoff. Fields left zero take the defaults of
+// NewExponentialBackoffRetryPolicy.
+type ExponentialBackoffRetryPolicy struct {
+	// InitialInterval is the backoff before the first retry. Defaults to
+	// DefaultBackoffInitialInterval.
+	InitialInterval time.Duration
+	// MaxInterval caps the backoff of a single retry. Defaults to
+	// DefaultBackoffMaxInterval.
+	MaxInterval time.Duration
+	// Multiplier is the factor the backoff is multiplied by after every
+	// attempt. Defaults to DefaultBackoffMultiplier.
+	Multiplier float64
+	// MaxElapsedTime stops retrying once the request has been running for
+	// this long. If MaxElapsedTime is 0, the request is retried until the
+	// number of retries is exhausted.
+	MaxElapsedTime time.Duration
+	// StatusRules overrides whether a response with the given status code is
+	// retried. By default 5xx and 429 responses are retried.
+	StatusRules map[int]bool
+}
+
+var _ RetryPolicy = (*ExponentialBackoffRetryPolicy)(nil)
+
+// NewExponentialBackoffRetryPolicy returns an ExponentialBackoffRetryPolicy
+// with default intervals and no elapsed time limit.
+func NewExponentialBackoffRetryPolicy() *ExponentialBackoffRetryPolicy {
+	return &ExponentialBackoffRetryPolicy{
+		InitialInterval: DefaultBackoffInitialInterval,
+		MaxInterval:     DefaultBackoffMaxInterval,
+		Multiplier:      DefaultBackoffMultiplier,
+	}
+}
+
+func (p *ExponentialBackoffRetryPolicy) NextRetry(attempt *RetryAttempt) (time.Duration, bool) {
+	if !isRetryableAttempt(attempt, p.StatusRules) {
+		return 0, false
+	}
+	if p.MaxElapsedTime > 0 && attempt.Elapsed >= p.MaxElapsedTime {
+		return 0, false
+	}
+
+	initialInterval, maxInterval, multiplier := p.InitialInterval, p.MaxInterval, p.Multiplier
+	if initialInterval <= 0 {
+		initialInterval = DefaultBackoffInitialInterval
+	}
+	if maxInterval <= 0 {
+		maxInterval = DefaultBackoffMaxInterval
+	}
+	if multiplier <= 0 {
+		multiplier = DefaultBackoffMultiplier
+	}
+
+	backoff := float64(initialInterval)
+	for i := 1; i < attempt.Attempt && backoff < float64(maxInterval); i++ {
+		backoff *= multiplier
+	}
+	backoff = min(backoff, float64(maxInterval))
+	if p.MaxElapsedTime > 0 {
+		backoff = min(backoff, float64(p.MaxElapsedTime-attempt.Elapsed))
+	}
+	if backoff <= 0 {
+		return 0, true
+	}
+	return time.Duration(retryJitterInt64N(int64(backoff) + 1)), true
+}
+
+// sleepContext waits for the given duration or until ctx is done,
+// whichever happens first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// exceedsDeadline reports whether waiting for d would run past the deadline of ctx.
+func exceedsDeadline(ctx context.Context, d time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < d
+}
+
+// isThrottled reports whether the server rejected the request because of rate
+// limiting or overload, i.e. responded with 429 or with 503 and a Retry-After header.
+func isThrottled(response *http.Response) bool {
+	if response == nil {
+		return false
+	}
+	switch response.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusServiceUnavailable:
+		return response.Header.Get("Retry-After") != ""
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter returns the wait time requested by the Retry-After header
+// of the response, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(response *http.Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+type overloadRetriesContextKey struct{}
+
+// contextWithoutOverloadRetries makes APICall return the 429 and 503 responses
+// of the requests made with the returned context instead of retrying them, for
+// callers that back off on their own.
+func contextWithoutOverloadRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, overloadRetriesContextKey{}, false)
+}
+
+// isOverloaded reports whether the server responded with 429 or 503, and the
+// request must not be retried by APICall because of it.
+func isOverloaded(ctx context.Context, response *http.Response) bool {
+	if retry, ok := ctx.Value(overloadRetriesContextKey{}).(bool); !ok || retry {
+		return false
+	}
+	return response != nil &&
+		(response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusServiceUnavailable)
+}
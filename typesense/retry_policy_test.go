@@ -0,0 +1,179 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stubRetryJitter(t *testing.T) {
+	t.Helper()
+	// always pick the upper bound so that the backoff is deterministic
+	retryJitterInt64N = func(n int64) int64 { return n - 1 }
+	t.Cleanup(func() {
+		retryJitterInt64N = rand.Int64N
+	})
+}
+
+func TestFixedIntervalRetryPolicy(t *testing.T) {
+	policy := &FixedIntervalRetryPolicy{Interval: 50 * time.Millisecond, StatusRules: map[int]bool{501: false, 429: true}}
+
+	tests := []struct {
+		name      string
+		attempt   *RetryAttempt
+		wantWait  time.Duration
+		wantRetry bool
+	}{
+		{
+			name:      "TransportError",
+			attempt:   &RetryAttempt{Attempt: 1, Err: errors.New("connection refused")},
+			wantWait:  50 * time.Millisecond,
+			wantRetry: true,
+		},
+		{
+			name:      "ServerError",
+			attempt:   &RetryAttempt{Attempt: 1, Response: &http.Response{StatusCode: 503}},
+			wantWait:  50 * time.Millisecond,
+			wantRetry: true,
+		},
+		{
+			name:      "ClientError",
+			attempt:   &RetryAttempt{Attempt: 1, Response: &http.Response{StatusCode: 404}},
+			wantRetry: false,
+		},
+		{
+			name:      "StatusRuleDisablesRetry",
+			attempt:   &RetryAttempt{Attempt: 1, Response: &http.Response{StatusCode: 501}},
+			wantRetry: false,
+		},
+		{
+			name:      "StatusRuleEnablesRetry",
+			attempt:   &RetryAttempt{Attempt: 1, Response: &http.Response{StatusCode: 429}},
+			wantWait:  50 * time.Millisecond,
+			wantRetry: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, retry := policy.NextRetry(tt.attempt)
+			assert.Equal(t, tt.wantRetry, retry)
+			assert.Equal(t, tt.wantWait, wait)
+		})
+	}
+}
+
+func TestExponentialBackoffRetryPolicyGrowsAndCapsBackoff(t *testing.T) {
+	stubRetryJitter(t)
+	policy := &ExponentialBackoffRetryPolicy{
+		InitialInterval: 100 * time.Millisecond,
+		MaxInterval:     time.Second,
+		Multiplier:      2,
+	}
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for i, want := range expected {
+		wait, retry := policy.NextRetry(&RetryAttempt{Attempt: i + 1, Err: errors.New("timeout")})
+		assert.True(t, retry)
+		assert.Equal(t, want, wait, "attempt %d", i+1)
+	}
+}
+
+func TestExponentialBackoffRetryPolicyAppliesFullJitter(t *testing.T) {
+	policy := NewExponentialBackoffRetryPolicy()
+	for i := 0; i < 100; i++ {
+		wait, retry := policy.NextRetry(&RetryAttempt{Attempt: 3, Err: errors.New("timeout")})
+		assert.True(t, retry)
+		assert.GreaterOrEqual(t, wait, time.Duration(0))
+		assert.LessOrEqual(t, wait, 4*DefaultBackoffInitialInterval)
+	}
+}
+
+func TestExponentialBackoffRetryPolicyZeroValueUsesDefaults(t *testing.T) {
+	stubRetryJitter(t)
+	policy := &ExponentialBackoffRetryPolicy{}
+
+	wait, retry := policy.NextRetry(&RetryAttempt{Attempt: 1, Err: errors.New("timeout")})
+	assert.True(t, retry)
+	assert.Equal(t, DefaultBackoffInitialInterval, wait)
+
+	wait, _ = policy.NextRetry(&RetryAttempt{Attempt: 3, Err: errors.New("timeout")})
+	assert.Equal(t, 4*DefaultBackoffInitialInterval, wait)
+
+	wait, _ = policy.NextRetry(&RetryAttempt{Attempt: 20, Err: errors.New("timeout")})
+	assert.Equal(t, DefaultBackoffMaxInterval, wait)
+}
+
+func TestExponentialBackoffRetryPolicyRespectsMaxElapsedTime(t *testing.T) {
+	stubRetryJitter(t)
+	policy := &ExponentialBackoffRetryPolicy{
+		InitialInterval: time.Second,
+		Multiplier:      2,
+		MaxElapsedTime:  1500 * time.Millisecond,
+	}
+
+	wait, retry := policy.NextRetry(&RetryAttempt{Attempt: 1, Elapsed: time.Second, Err: errors.New("timeout")})
+	assert.True(t, retry)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	_, retry = policy.NextRetry(&RetryAttempt{Attempt: 2, Elapsed: 1500 * time.Millisecond, Err: errors.New("timeout")})
+	assert.False(t, retry)
+}
+
+func TestSleepContextIsAbortedWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := sleepContext(ctx, time.Minute)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantWait time.Duration
+		wantOk   bool
+	}{
+		{name: "Missing", header: "", wantOk: false},
+		{name: "Seconds", header: "3", wantWait: 3 * time.Second, wantOk: true},
+		{name: "PastDate", header: "Wed, 21 Oct 2015 07:28:00 GMT", wantWait: 0, wantOk: true},
+		{name: "Invalid", header: "soon", wantOk: false},
+		{name: "Negative", header: "-1", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				response.Header.Set("Retry-After", tt.header)
+			}
+			wait, ok := parseRetryAfter(response)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantWait, wait)
+		})
+	}
+}
+
+func TestIsThrottled(t *testing.T) {
+	withRetryAfter := http.Header{}
+	withRetryAfter.Set("Retry-After", "1")
+
+	assert.True(t, isThrottled(&http.Response{StatusCode: 429, Header: http.Header{}}))
+	assert.True(t, isThrottled(&http.Response{StatusCode: 503, Header: withRetryAfter}))
+	assert.False(t, isThrottled(&http.Response{StatusCode: 503, Header: http.Header{}}))
+	assert.False(t, isThrottled(&http.Response{StatusCode: 200, Header: withRetryAfter}))
+	assert.False(t, isThrottled(nil))
+}
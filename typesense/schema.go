@@ -0,0 +1,376 @@
+package typesense
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+)
+
+const schemaTagName = "typesense"
+
+// DefaultEmbeddingModel is the model used for fields with the embed_from option
+// if the embed_model option is not set.
+const DefaultEmbeddingModel = "ts/all-MiniLM-L12-v2"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaFromStruct returns the schema of a collection whose documents are of
+// the struct type T. The fields are derived from the exported fields of T, which
+// can be configured with a typesense tag:
+//
+//	type Book struct {
+//		ID        string     `json:"id"`
+//		Title     string     `json:"title" typesense:",sort,infix"`
+//		Genres    []string   `json:"genres" typesense:",facet"`
+//		Rating    float64    `json:"rating" typesense:",default_sort"`
+//		AuthorID  string     `json:"author_id" typesense:",reference=authors.id"`
+//		Location  [2]float64 `json:"location"`
+//		Embedding []float32  `json:"embedding" typesense:",embed_from=title|description"`
+//		Internal  string     `json:"-"`
+//	}
+//
+// The first item of the tag is the name of the field, which defaults to the
+// name in the json tag or else the name of the struct field. The other items
+// are options:
+//
+//   - facet, sort, optional, index, infix, stem, store, range_index and
+//     async_reference set the field option of the same name; they are true
+//     unless set to false, e.g. index=false
+//   - locale, reference, vec_dist and stem_dictionary set the field option of
+//     the same name, e.g. locale=ja
+//   - num_dim sets the number of dimensions of a vector field
+//   - embed_from lists the fields a float[] embedding is generated from,
+//     separated by |, using DefaultEmbeddingModel or the model set with embed_model
+//   - type overrides the Typesense type derived from the Go type, e.g. type=auto
+//   - default_sort makes the field the default sorting field of the collection
+//
+// Go types are mapped to Typesense types as follows:
+//
+//   - string: string
+//   - bool: bool
+//   - int8, int16, int32, uint8 and uint16: int32
+//   - int, int64, uint, uint32 and uint64: int64
+//   - float32 and float64: float
+//   - time.Time: string, since encoding/json encodes it in RFC 3339 format;
+//     store Unix timestamps in an int64 field to sort or filter by time
+//   - [2]float32 and [2]float64: geopoint, as latitude and longitude
+//   - structs and maps: object, enabling nested fields in the schema
+//   - interfaces: auto
+//   - slices and arrays: the array type of their elements, e.g. string[]
+//     or geopoint[]; []byte is a string since it is encoded in base64
+//
+// Pointer fields and fields with the omitempty json option are optional.
+// The fields of nested structs are declared as well if they have a typesense
+// tag, e.g. "author.name" for the field Name of the nested struct Author.
+func SchemaFromStruct[T any](name string) (*api.CollectionSchema, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot derive schema from %s: not a struct", t)
+	}
+
+	b := &schemaBuilder{expanding: map[expandingStruct]bool{}}
+	fields, err := b.structFields(t, "", false, false)
+	if err != nil {
+		return nil, err
+	}
+	schema := &api.CollectionSchema{Name: name, Fields: fields}
+	if b.defaultSortingField != "" {
+		schema.DefaultSortingField = pointer.String(b.defaultSortingField)
+	}
+	if b.nestedFields {
+		schema.EnableNestedFields = pointer.True()
+	}
+	return schema, nil
+}
+
+// FieldsFromStruct returns the fields of a collection whose documents are of the
+// struct type T, see SchemaFromStruct.
+func FieldsFromStruct[T any]() ([]api.Field, error) {
+	schema, err := SchemaFromStruct[T]("")
+	if err != nil {
+		return nil, err
+	}
+	return schema.Fields, nil
+}
+
+type schemaBuilder struct {
+	defaultSortingField string
+	nestedFields        bool
+	// expanding are the structs whose fields are being derived, to detect types
+	// that contain themselves through tagged fields
+	expanding map[expandingStruct]bool
+}
+
+type expandingStruct struct {
+	t          reflect.Type
+	onlyTagged bool
+}
+
+// structFields returns the fields of the struct type t. The names of the fields
+// are prefixed with prefix and their types are arrays if inArray is true, which
+// is the case for the fields of structs nested in an array.
+func (b *schemaBuilder) structFields(t reflect.Type, prefix string, inArray bool, onlyTagged bool) ([]api.Field, error) {
+	b.expanding[expandingStruct{t, onlyTagged}] = true
+	defer delete(b.expanding, expandingStruct{t, onlyTagged})
+
+	var fields []api.Field
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		tag, hasTag := structField.Tag.Lookup(schemaTagName)
+		if tag == "-" || (!structField.IsExported() && !structField.Anonymous) {
+			continue
+		}
+		jsonName, jsonOmitEmpty, jsonSkip := parseJSONTag(structField.Tag.Get("json"))
+		if jsonSkip && !hasTag {
+			continue
+		}
+
+		fieldType := structField.Type
+		optional := jsonOmitEmpty
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+			optional = true
+		}
+
+		// the fields of embedded structs are promoted, like encoding/json does
+		if structField.Anonymous && !hasTag && jsonName == "" && fieldType.Kind() == reflect.Struct {
+			if b.expanding[expandingStruct{fieldType, onlyTagged}] {
+				return nil, fmt.Errorf("cannot derive fields of field %s: type %s contains itself", structField.Name, fieldType)
+			}
+			embedded, err := b.structFields(fieldType, prefix, inArray, onlyTagged)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, embedded...)
+			continue
+		}
+		if !structField.IsExported() || (onlyTagged && !hasTag) {
+			continue
+		}
+
+		name, options := parseSchemaTag(tag)
+		if name == "" {
+			name = jsonName
+		}
+		if name == "" {
+			name = structField.Name
+		}
+		field := api.Field{Name: prefix + name}
+		if optional {
+			field.Optional = pointer.True()
+		}
+
+		typ, nested, isArray, err := typesenseType(fieldType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot derive type of field %s: %w", structField.Name, err)
+		}
+		if inArray && !isArray {
+			typ += "[]"
+		}
+		field.Type = typ
+		for _, option := range options {
+			if err := b.applySchemaOption(&field, option); err != nil {
+				return nil, fmt.Errorf("invalid %s tag of field %s: %w", schemaTagName, structField.Name, err)
+			}
+		}
+		if field.Embed != nil && len(field.Embed.From) == 0 {
+			return nil, fmt.Errorf("invalid %s tag of field %s: option embed_model requires embed_from", schemaTagName, structField.Name)
+		}
+		fields = append(fields, field)
+
+		if strings.HasPrefix(typ, "object") {
+			b.nestedFields = true
+		}
+		if nested != nil {
+			if b.expanding[expandingStruct{nested, true}] {
+				return nil, fmt.Errorf("cannot derive fields of field %s: type %s contains itself", structField.Name, nested)
+			}
+			nestedFields, err := b.structFields(nested, field.Name+".", inArray || isArray, true)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nestedFields...)
+		}
+	}
+	return fields, nil
+}
+
+// typesenseType returns the Typesense type of the Go type t, the struct type of
+// its fields if it is an object or an object array, and whether it is an array.
+func typesenseType(t reflect.Type) (typ string, nested reflect.Type, isArray bool, err error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return "string", nil, false, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string", nil, false, nil
+	case reflect.Bool:
+		return "bool", nil, false, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return "int32", nil, false, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "int64", nil, false, nil
+	case reflect.Float32, reflect.Float64:
+		return "float", nil, false, nil
+	case reflect.Struct:
+		return "object", t, false, nil
+	case reflect.Map:
+		return "object", nil, false, nil
+	case reflect.Interface:
+		return "auto", nil, false, nil
+	case reflect.Array, reflect.Slice:
+		if isGeopoint(t) {
+			return "geopoint", nil, false, nil
+		}
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return "string", nil, false, nil
+		}
+		elemType, nested, elemIsArray, err := typesenseType(t.Elem())
+		if err != nil {
+			return "", nil, false, err
+		}
+		switch {
+		case elemType == "auto":
+			return "auto", nil, false, nil
+		case elemIsArray:
+			return "", nil, false, fmt.Errorf("nested arrays of %s are not supported", t.Elem())
+		}
+		return elemType + "[]", nested, true, nil
+	default:
+		return "", nil, false, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// isGeopoint reports whether t is a latitude and longitude pair.
+func isGeopoint(t reflect.Type) bool {
+	if t.Kind() != reflect.Array || t.Len() != 2 {
+		return false
+	}
+	kind := t.Elem().Kind()
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+type schemaOption struct {
+	key   string
+	value string
+}
+
+func parseSchemaTag(tag string) (string, []schemaOption) {
+	items := strings.Split(tag, ",")
+	options := make([]schemaOption, 0, len(items)-1)
+	for _, item := range items[1:] {
+		if item == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(item, "=")
+		options = append(options, schemaOption{key: strings.TrimSpace(key), value: strings.TrimSpace(value)})
+	}
+	return items[0], options
+}
+
+func parseJSONTag(tag string) (name string, omitEmpty bool, skip bool) {
+	if tag == "-" {
+		return "", false, true
+	}
+	items := strings.Split(tag, ",")
+	for _, item := range items[1:] {
+		if item == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return items[0], omitEmpty, false
+}
+
+func (b *schemaBuilder) applySchemaOption(field *api.Field, option schemaOption) error {
+	parseBool := func() (*bool, error) {
+		if option.value == "" {
+			return pointer.True(), nil
+		}
+		value, err := strconv.ParseBool(option.value)
+		if err != nil {
+			return nil, fmt.Errorf("option %s: %w", option.key, err)
+		}
+		return &value, nil
+	}
+	requireValue := func() (*string, error) {
+		if option.value == "" {
+			return nil, fmt.Errorf("option %s requires a value", option.key)
+		}
+		return pointer.String(option.value), nil
+	}
+
+	var err error
+	switch option.key {
+	case "facet":
+		field.Facet, err = parseBool()
+	case "sort":
+		field.Sort, err = parseBool()
+	case "optional":
+		field.Optional, err = parseBool()
+	case "index":
+		field.Index, err = parseBool()
+	case "infix":
+		field.Infix, err = parseBool()
+	case "stem":
+		field.Stem, err = parseBool()
+	case "store":
+		field.Store, err = parseBool()
+	case "range_index":
+		field.RangeIndex, err = parseBool()
+	case "async_reference":
+		field.AsyncReference, err = parseBool()
+	case "locale":
+		field.Locale, err = requireValue()
+	case "reference":
+		field.Reference, err = requireValue()
+	case "vec_dist":
+		field.VecDist, err = requireValue()
+	case "stem_dictionary":
+		field.StemDictionary, err = requireValue()
+	case "type":
+		var typ *string
+		if typ, err = requireValue(); err == nil {
+			field.Type = *typ
+		}
+	case "num_dim":
+		var numDim int
+		if numDim, err = strconv.Atoi(option.value); err != nil {
+			return fmt.Errorf("option %s: %w", option.key, err)
+		}
+		field.NumDim = &numDim
+	case "embed_from":
+		if option.value == "" {
+			return fmt.Errorf("option %s requires a value", option.key)
+		}
+		if field.Embed == nil {
+			field.Embed = &api.FieldEmbed{}
+			field.Embed.ModelConfig.ModelName = DefaultEmbeddingModel
+		}
+		field.Embed.From = strings.Split(option.value, "|")
+		field.Type = "float[]"
+	case "embed_model":
+		if option.value == "" {
+			return fmt.Errorf("option %s requires a value", option.key)
+		}
+		if field.Embed == nil {
+			field.Embed = &api.FieldEmbed{}
+		}
+		field.Embed.ModelConfig.ModelName = option.value
+	case "default_sort":
+		b.defaultSortingField = field.Name
+	default:
+		return fmt.Errorf("unknown option %q", option.key)
+	}
+	return err
+}
@@ -0,0 +1,396 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+)
+
+// ErrReindexRequired is returned by SchemaPlan.Apply if the schema change cannot
+// be applied to the existing collection and the documents have to be indexed
+// into a new collection instead.
+var ErrReindexRequired = errors.New("schema change requires reindex")
+
+// SchemaPlan is the difference between the live schema of a collection and its
+// desired schema, see CollectionsPlannerInterface.Plan.
+type SchemaPlan struct {
+	Collection string
+	// Desired is the schema the plan was computed for.
+	Desired *api.CollectionSchema
+	// Create is true if the collection does not exist yet.
+	Create bool
+	// Added, Dropped and Changed are the fields to be added, dropped and changed.
+	Added   []FieldChange
+	Dropped []FieldChange
+	Changed []FieldChange
+	// CollectionChanges are the changes of collection settings, e.g. default_sorting_field.
+	CollectionChanges []AttributeChange
+	// ReindexReasons lists why the plan cannot be applied in place, if it cannot.
+	ReindexReasons []string
+
+	apiClient APIClientInterface
+}
+
+// FieldChange describes a field to be added, dropped or changed.
+type FieldChange struct {
+	Name string
+	// Current is the live field, nil if the field is added.
+	Current *api.Field
+	// Desired is the desired field, nil if the field is dropped.
+	Desired *api.Field
+	// Attributes are the changed attributes of a changed field.
+	Attributes []AttributeChange
+	// RequiresReindex is true if the change cannot be applied in place.
+	RequiresReindex bool
+}
+
+// AttributeChange is the change of a single attribute of a field or collection,
+// with values formatted for display.
+type AttributeChange struct {
+	Name string
+	From string
+	To   string
+	// RequiresReindex is true if the attribute cannot be changed in place.
+	RequiresReindex bool
+}
+
+// HasChanges reports whether applying the plan would change anything.
+func (p *SchemaPlan) HasChanges() bool {
+	return p.Create || len(p.Added) > 0 || len(p.Dropped) > 0 || len(p.Changed) > 0 || len(p.CollectionChanges) > 0
+}
+
+// RequiresReindex reports whether the plan cannot be applied in place, i.e.
+// the documents have to be indexed into a new collection with the desired schema.
+func (p *SchemaPlan) RequiresReindex() bool {
+	return len(p.ReindexReasons) > 0
+}
+
+// UpdateSchema returns the update that applies the field changes of the plan to
+// the collection. Changed fields are dropped and added again in the same update.
+func (p *SchemaPlan) UpdateSchema() *api.CollectionUpdateSchema {
+	update := &api.CollectionUpdateSchema{Fields: []api.Field{}}
+	for _, change := range append(append([]FieldChange{}, p.Dropped...), p.Changed...) {
+		update.Fields = append(update.Fields, api.Field{Name: change.Name, Drop: pointer.True()})
+	}
+	for _, change := range append(append([]FieldChange{}, p.Changed...), p.Added...) {
+		update.Fields = append(update.Fields, *change.Desired)
+	}
+	for _, change := range p.CollectionChanges {
+		switch change.Name {
+		case "metadata":
+			update.Metadata = p.Desired.Metadata
+		case "synonym_sets":
+			update.SynonymSets = p.Desired.SynonymSets
+		}
+	}
+	return update
+}
+
+// Apply creates the collection or updates its schema as planned. It returns an
+// error wrapping ErrReindexRequired without making any change if the plan
+// cannot be applied in place.
+func (p *SchemaPlan) Apply(ctx context.Context) error {
+	if p.apiClient == nil {
+		return errors.New("schema plan was not returned by CollectionsPlannerInterface.Plan")
+	}
+	if p.Create {
+		_, err := (&collections{apiClient: p.apiClient}).Create(ctx, p.Desired)
+		return err
+	}
+	if p.RequiresReindex() {
+		return fmt.Errorf("%w: %s", ErrReindexRequired, strings.Join(p.ReindexReasons, "; "))
+	}
+	if !p.HasChanges() {
+		return nil
+	}
+	_, err := (&collection[any]{apiClient: p.apiClient, name: p.Collection}).Update(ctx, p.UpdateSchema())
+	return err
+}
+
+// String formats the plan for review, one change per line:
+//
+//	collection products:
+//	  + field brand (string)
+//	  - field legacy_id (int64)
+//	  ~ field price: type int32 -> float
+func (p *SchemaPlan) String() string {
+	var b strings.Builder
+	switch {
+	case p.Create:
+		fmt.Fprintf(&b, "create collection %s:\n", p.Collection)
+		for _, field := range p.Desired.Fields {
+			fmt.Fprintf(&b, "  + field %s (%s)\n", field.Name, field.Type)
+		}
+		return b.String()
+	case !p.HasChanges():
+		return fmt.Sprintf("collection %s: no changes\n", p.Collection)
+	}
+
+	fmt.Fprintf(&b, "collection %s:\n", p.Collection)
+	for _, change := range p.Added {
+		fmt.Fprintf(&b, "  + field %s (%s)\n", change.Name, change.Desired.Type)
+	}
+	for _, change := range p.Dropped {
+		fmt.Fprintf(&b, "  - field %s (%s)\n", change.Name, change.Current.Type)
+	}
+	for _, change := range p.Changed {
+		attributes := make([]string, 0, len(change.Attributes))
+		for _, attribute := range change.Attributes {
+			attributes = append(attributes, attribute.String())
+		}
+		fmt.Fprintf(&b, "  ~ field %s: %s%s\n", change.Name, strings.Join(attributes, ", "), reindexSuffix(change.RequiresReindex))
+	}
+	for _, change := range p.CollectionChanges {
+		fmt.Fprintf(&b, "  ~ %s%s\n", change.String(), reindexSuffix(change.RequiresReindex))
+	}
+	for _, reason := range p.ReindexReasons {
+		fmt.Fprintf(&b, "requires reindex: %s\n", reason)
+	}
+	return b.String()
+}
+
+func (c AttributeChange) String() string {
+	return fmt.Sprintf("%s %s -> %s", c.Name, c.From, c.To)
+}
+
+func reindexSuffix(requiresReindex bool) string {
+	if requiresReindex {
+		return " (requires reindex)"
+	}
+	return ""
+}
+
+func (c *collections) Plan(ctx context.Context, desired *api.CollectionSchema) (*SchemaPlan, error) {
+	response, err := c.apiClient.GetCollectionWithResponse(ctx, desired.Name)
+	if err != nil {
+		return nil, err
+	}
+	if response.JSON200 == nil {
+		if response.StatusCode() == http.StatusNotFound {
+			return &SchemaPlan{Collection: desired.Name, Desired: desired, Create: true, apiClient: c.apiClient}, nil
+		}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
+	}
+	plan := PlanSchema(response.JSON200, desired)
+	plan.apiClient = c.apiClient
+	return plan, nil
+}
+
+// PlanSchema returns the difference between the live schema of a collection and
+// its desired schema. The plan can only be applied if it is returned by
+// CollectionsPlannerInterface.Plan.
+//
+// Attributes that are not set in the desired schema are compared with their
+// default values, except for Metadata and SynonymSets, which are only compared
+// if they are set. The subfields of object fields and the fields matching
+// wildcard or regex fields, e.g. ".*", that are not declared in the desired
+// schema are ignored, since Typesense adds them to the live schema.
+func PlanSchema(current *api.CollectionResponse, desired *api.CollectionSchema) *SchemaPlan {
+	plan := &SchemaPlan{Collection: desired.Name, Desired: desired}
+
+	currentSortingField := stringValue(current.DefaultSortingField)
+	currentFields := make(map[string]*api.Field, len(current.Fields))
+	for i := range current.Fields {
+		currentFields[current.Fields[i].Name] = &current.Fields[i]
+	}
+	desiredFields := make(map[string]*api.Field, len(desired.Fields))
+	var objectFields []string
+	var patternFields []*regexp.Regexp
+	for i := range desired.Fields {
+		field := &desired.Fields[i]
+		desiredFields[field.Name] = field
+		if strings.HasPrefix(field.Type, "object") {
+			objectFields = append(objectFields, field.Name+".")
+		}
+		if strings.Contains(field.Name, ".*") {
+			if pattern, err := regexp.Compile("^(?:" + field.Name + ")$"); err == nil {
+				patternFields = append(patternFields, pattern)
+			}
+		}
+
+		currentField, ok := currentFields[field.Name]
+		if !ok {
+			plan.Added = append(plan.Added, FieldChange{Name: field.Name, Desired: field})
+			continue
+		}
+		attributes := diffFields(currentField, field)
+		if len(attributes) == 0 {
+			continue
+		}
+		change := FieldChange{Name: field.Name, Current: currentField, Desired: field, Attributes: attributes}
+		if currentField.Store != nil && !*currentField.Store {
+			change.RequiresReindex = true
+			plan.ReindexReasons = append(plan.ReindexReasons, fmt.Sprintf("values of field %s are not stored", field.Name))
+		}
+		if field.Name == currentSortingField {
+			change.RequiresReindex = true
+			plan.ReindexReasons = append(plan.ReindexReasons, fmt.Sprintf("field %s is the default sorting field", field.Name))
+		}
+		plan.Changed = append(plan.Changed, change)
+	}
+
+	for i := range current.Fields {
+		field := &current.Fields[i]
+		if _, ok := desiredFields[field.Name]; ok || hasAnyPrefix(field.Name, objectFields) || matchesAny(field.Name, patternFields) {
+			continue
+		}
+		change := FieldChange{Name: field.Name, Current: field}
+		if field.Name == currentSortingField {
+			change.RequiresReindex = true
+			plan.ReindexReasons = append(plan.ReindexReasons, fmt.Sprintf("field %s is the default sorting field", field.Name))
+		}
+		plan.Dropped = append(plan.Dropped, change)
+	}
+
+	for _, setting := range []struct {
+		name            string
+		current, wanted string
+	}{
+		{"default_sorting_field", currentSortingField, stringValue(desired.DefaultSortingField)},
+		{"enable_nested_fields", boolValue(current.EnableNestedFields, false), boolValue(desired.EnableNestedFields, false)},
+		{"token_separators", listValue(current.TokenSeparators), listValue(desired.TokenSeparators)},
+		{"symbols_to_index", listValue(current.SymbolsToIndex), listValue(desired.SymbolsToIndex)},
+	} {
+		if setting.current != setting.wanted {
+			plan.CollectionChanges = append(plan.CollectionChanges, AttributeChange{
+				Name: setting.name, From: setting.current, To: setting.wanted, RequiresReindex: true,
+			})
+			plan.ReindexReasons = append(plan.ReindexReasons, fmt.Sprintf("%s cannot be changed", setting.name))
+		}
+	}
+	if desired.Metadata != nil {
+		if from, to := jsonValue(current.Metadata), jsonValue(desired.Metadata); from != to {
+			plan.CollectionChanges = append(plan.CollectionChanges, AttributeChange{Name: "metadata", From: from, To: to})
+		}
+	}
+	if desired.SynonymSets != nil {
+		if from, to := listValue(current.SynonymSets), listValue(desired.SynonymSets); from != to {
+			plan.CollectionChanges = append(plan.CollectionChanges, AttributeChange{Name: "synonym_sets", From: from, To: to})
+		}
+	}
+	return plan
+}
+
+// fieldAttributes formats the attributes of a field that are compared by
+// PlanSchema, with unset attributes formatted as their default value.
+// Attributes with isSet are filled in by the server, e.g. num_dim from the
+// model of an embedding, and only compared if they are set in the desired field.
+var fieldAttributes = []struct {
+	name  string
+	value func(field *api.Field) string
+	isSet func(field *api.Field) bool
+}{
+	{"type", func(f *api.Field) string { return f.Type }, nil},
+	{"optional", func(f *api.Field) string { return boolValue(f.Optional, false) }, nil},
+	{"facet", func(f *api.Field) string { return boolValue(f.Facet, false) }, nil},
+	{"index", func(f *api.Field) string { return boolValue(f.Index, true) }, nil},
+	{"sort", func(f *api.Field) string { return boolValue(f.Sort, sortsByDefault(f.Type)) }, nil},
+	{"infix", func(f *api.Field) string { return boolValue(f.Infix, false) }, nil},
+	{"locale", func(f *api.Field) string { return stringValue(f.Locale) }, nil},
+	{"stem", func(f *api.Field) string { return boolValue(f.Stem, false) }, nil},
+	{"stem_dictionary", func(f *api.Field) string { return stringValue(f.StemDictionary) }, nil},
+	{"store", func(f *api.Field) string { return boolValue(f.Store, true) }, nil},
+	{"range_index", func(f *api.Field) string { return boolValue(f.RangeIndex, false) }, nil},
+	{"reference", func(f *api.Field) string { return stringValue(f.Reference) }, nil},
+	{"async_reference", func(f *api.Field) string { return boolValue(f.AsyncReference, false) }, nil},
+	{"num_dim", func(f *api.Field) string {
+		if f.NumDim == nil {
+			return "0"
+		}
+		return strconv.Itoa(*f.NumDim)
+	}, func(f *api.Field) bool { return f.NumDim != nil }},
+	{"vec_dist", func(f *api.Field) string {
+		if f.VecDist == nil || *f.VecDist == "" {
+			return "cosine"
+		}
+		return *f.VecDist
+	}, func(f *api.Field) bool { return f.VecDist != nil && *f.VecDist != "" }},
+	{"embed", func(f *api.Field) string {
+		if f.Embed == nil {
+			return `""`
+		}
+		return fmt.Sprintf("%s from %s", f.Embed.ModelConfig.ModelName, strings.Join(f.Embed.From, "|"))
+	}, nil},
+	{"token_separators", func(f *api.Field) string { return listValue(f.TokenSeparators) }, nil},
+	{"symbols_to_index", func(f *api.Field) string { return listValue(f.SymbolsToIndex) }, nil},
+}
+
+func diffFields(current, desired *api.Field) []AttributeChange {
+	var changes []AttributeChange
+	for _, attribute := range fieldAttributes {
+		if attribute.isSet != nil && !attribute.isSet(desired) {
+			continue
+		}
+		if from, to := attribute.value(current), attribute.value(desired); from != to {
+			changes = append(changes, AttributeChange{Name: attribute.name, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+// sortsByDefault reports whether fields of the type are sortable unless sort is set to false.
+func sortsByDefault(typ string) bool {
+	switch typ {
+	case "int32", "int64", "float", "bool", "geopoint":
+		return true
+	default:
+		return false
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(s string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValue(value *string) string {
+	if value == nil || *value == "" {
+		return `""`
+	}
+	return *value
+}
+
+func boolValue(value *bool, defaultValue bool) string {
+	if value == nil {
+		return strconv.FormatBool(defaultValue)
+	}
+	return strconv.FormatBool(*value)
+}
+
+func listValue(value *[]string) string {
+	if value == nil || len(*value) == 0 {
+		return "[]"
+	}
+	return jsonValue(*value)
+}
+
+// jsonValue formats v as JSON, which sorts the keys of maps, so that equal
+// values are formatted the same even if they were decoded differently.
+func jsonValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,326 @@
+package typesense
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+	"github.com/typesense/typesense-go/v4/typesense/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+// liveProductsCollection is the schema of a collection as returned by the
+// server, which sets every field attribute explicitly.
+func liveProductsCollection() *api.CollectionResponse {
+	liveField := func(name, typ string, sort bool) api.Field {
+		return api.Field{
+			Name: name, Type: typ,
+			Facet: pointer.False(), Index: pointer.True(), Infix: pointer.False(),
+			Locale: pointer.String(""), Optional: pointer.False(), Sort: &sort,
+			Stem: pointer.False(), Store: pointer.True(), RangeIndex: pointer.False(),
+		}
+	}
+	brand := liveField("brand", "string", false)
+	brand.Facet = pointer.True()
+	description := liveField("description", "string", false)
+	description.Store = pointer.False()
+	return &api.CollectionResponse{
+		Name: "products",
+		Fields: []api.Field{
+			liveField("title", "string", false),
+			brand,
+			liveField("price", "int32", true),
+			liveField("legacy_id", "int64", true),
+			liveField("popularity", "int32", true),
+			description,
+			liveField("seller", "object", false),
+			liveField("seller.name", "string", false),
+		},
+		DefaultSortingField: pointer.String("popularity"),
+		EnableNestedFields:  pointer.True(),
+		NumDocuments:        pointer.Int64(100),
+	}
+}
+
+func desiredProductsSchema() *api.CollectionSchema {
+	return &api.CollectionSchema{
+		Name: "products",
+		Fields: []api.Field{
+			{Name: "title", Type: "string"},
+			{Name: "brand", Type: "string", Facet: pointer.True()},
+			{Name: "price", Type: "float"},
+			{Name: "popularity", Type: "int32"},
+			{Name: "description", Type: "string", Store: pointer.False()},
+			{Name: "seller", Type: "object"},
+			{Name: "in_stock", Type: "bool", Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("popularity"),
+		EnableNestedFields:  pointer.True(),
+	}
+}
+
+func TestSchemaPlanInPlace(t *testing.T) {
+	desired := desiredProductsSchema()
+	plan := PlanSchema(liveProductsCollection(), desired)
+
+	assert.True(t, plan.HasChanges())
+	assert.False(t, plan.RequiresReindex())
+	assert.Equal(t, []FieldChange{{Name: "in_stock", Desired: &desired.Fields[6]}}, plan.Added)
+	if assert.Len(t, plan.Dropped, 1) {
+		assert.Equal(t, "legacy_id", plan.Dropped[0].Name)
+	}
+	if assert.Len(t, plan.Changed, 1) {
+		assert.Equal(t, "price", plan.Changed[0].Name)
+		assert.Equal(t, []AttributeChange{{Name: "type", From: "int32", To: "float"}}, plan.Changed[0].Attributes)
+		assert.False(t, plan.Changed[0].RequiresReindex)
+	}
+	assert.Empty(t, plan.CollectionChanges)
+
+	assert.Equal(t, &api.CollectionUpdateSchema{
+		Fields: []api.Field{
+			{Name: "legacy_id", Drop: pointer.True()},
+			{Name: "price", Drop: pointer.True()},
+			{Name: "price", Type: "float"},
+			{Name: "in_stock", Type: "bool", Optional: pointer.True()},
+		},
+	}, plan.UpdateSchema())
+
+	assert.Equal(t, "collection products:\n"+
+		"  + field in_stock (bool)\n"+
+		"  - field legacy_id (int64)\n"+
+		"  ~ field price: type int32 -> float\n", plan.String())
+}
+
+func TestSchemaPlanWithoutChanges(t *testing.T) {
+	desired := desiredProductsSchema()
+	desired.Fields = []api.Field{
+		{Name: "title", Type: "string"},
+		{Name: "brand", Type: "string", Facet: pointer.True()},
+		{Name: "price", Type: "int32"},
+		{Name: "legacy_id", Type: "int64", Sort: pointer.True()},
+		{Name: "popularity", Type: "int32"},
+		{Name: "description", Type: "string", Store: pointer.False()},
+		{Name: "seller", Type: "object"},
+	}
+	plan := PlanSchema(liveProductsCollection(), desired)
+
+	assert.False(t, plan.HasChanges())
+	assert.Equal(t, "collection products: no changes\n", plan.String())
+}
+
+func TestSchemaPlanRequiresReindex(t *testing.T) {
+	desired := desiredProductsSchema()
+	desired.Fields[3] = api.Field{Name: "popularity", Type: "float"}
+	desired.Fields[4] = api.Field{Name: "description", Type: "string", Store: pointer.False(), Locale: pointer.String("ja")}
+	desired.TokenSeparators = &[]string{"-"}
+	plan := PlanSchema(liveProductsCollection(), desired)
+
+	assert.True(t, plan.RequiresReindex())
+	assert.Equal(t, []string{
+		"field popularity is the default sorting field",
+		"values of field description are not stored",
+		"token_separators cannot be changed",
+	}, plan.ReindexReasons)
+	assert.Equal(t, []AttributeChange{
+		{Name: "token_separators", From: "[]", To: `["-"]`, RequiresReindex: true},
+	}, plan.CollectionChanges)
+	assert.Equal(t, "collection products:\n"+
+		"  + field in_stock (bool)\n"+
+		"  - field legacy_id (int64)\n"+
+		"  ~ field price: type int32 -> float\n"+
+		"  ~ field popularity: type int32 -> float (requires reindex)\n"+
+		"  ~ field description: locale \"\" -> ja (requires reindex)\n"+
+		"  ~ token_separators [] -> [\"-\"] (requires reindex)\n"+
+		"requires reindex: field popularity is the default sorting field\n"+
+		"requires reindex: values of field description are not stored\n"+
+		"requires reindex: token_separators cannot be changed\n", plan.String())
+}
+
+func TestSchemaPlanMetadataAndSynonymSets(t *testing.T) {
+	live := liveProductsCollection()
+	live.Metadata = &map[string]interface{}{"owner": "search", "version": float64(1)}
+	desired := desiredProductsSchema()
+	desired.Fields = nil
+	desired.Metadata = &map[string]interface{}{"owner": "search", "version": 1}
+	desired.SynonymSets = &[]string{"products"}
+	live.Fields = nil
+
+	plan := PlanSchema(live, desired)
+
+	assert.False(t, plan.RequiresReindex())
+	assert.Equal(t, []AttributeChange{{Name: "synonym_sets", From: "[]", To: `["products"]`}}, plan.CollectionChanges)
+	assert.Equal(t, &api.CollectionUpdateSchema{Fields: []api.Field{}, SynonymSets: &[]string{"products"}}, plan.UpdateSchema())
+}
+
+func TestSchemaPlanComparesAttributesFilledInByServerOnlyIfSet(t *testing.T) {
+	embed := &api.FieldEmbed{From: []string{"title"}}
+	embed.ModelConfig.ModelName = DefaultEmbeddingModel
+	live := liveProductsCollection()
+	live.Fields = append(live.Fields, api.Field{
+		Name: "embedding", Type: "float[]", Embed: embed, NumDim: pointer.Int(384), VecDist: pointer.String("cosine"),
+		Facet: pointer.False(), Index: pointer.True(), Infix: pointer.False(), Locale: pointer.String(""),
+		Optional: pointer.False(), Sort: pointer.False(), Stem: pointer.False(), Store: pointer.True(), RangeIndex: pointer.False(),
+	})
+	desired := desiredProductsSchema()
+	desired.Fields = []api.Field{
+		{Name: "title", Type: "string"},
+		{Name: "brand", Type: "string", Facet: pointer.True()},
+		{Name: "price", Type: "int32"},
+		{Name: "legacy_id", Type: "int64", Sort: pointer.True()},
+		{Name: "popularity", Type: "int32"},
+		{Name: "description", Type: "string", Store: pointer.False()},
+		{Name: "seller", Type: "object"},
+		{Name: "embedding", Type: "float[]", Embed: embed},
+	}
+
+	plan := PlanSchema(live, desired)
+	assert.False(t, plan.HasChanges())
+
+	desired.Fields[7].NumDim = pointer.Int(768)
+	plan = PlanSchema(live, desired)
+	if assert.Len(t, plan.Changed, 1) {
+		assert.Equal(t, []AttributeChange{{Name: "num_dim", From: "384", To: "768"}}, plan.Changed[0].Attributes)
+	}
+}
+
+func TestSchemaPlanKeepsFieldsMatchingWildcardFields(t *testing.T) {
+	live := &api.CollectionResponse{
+		Name: "products",
+		Fields: []api.Field{
+			{Name: ".*", Type: "auto", Optional: pointer.True()},
+			{Name: "color", Type: "string", Optional: pointer.True()},
+		},
+	}
+	desired := &api.CollectionSchema{
+		Name:   "products",
+		Fields: []api.Field{{Name: ".*", Type: "auto", Optional: pointer.True()}},
+	}
+
+	assert.False(t, PlanSchema(live, desired).HasChanges())
+}
+
+func TestSchemaPlanKeepsFieldsMatchingRegexFields(t *testing.T) {
+	live := &api.CollectionResponse{
+		Name: "products",
+		Fields: []api.Field{
+			{Name: ".*_facet", Type: "string", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "size_facet", Type: "string", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "legacy_id", Type: "int64"},
+		},
+	}
+	desired := &api.CollectionSchema{
+		Name:   "products",
+		Fields: []api.Field{{Name: ".*_facet", Type: "string", Facet: pointer.True(), Optional: pointer.True()}},
+	}
+
+	plan := PlanSchema(live, desired)
+
+	assert.Empty(t, plan.Changed)
+	assert.Empty(t, plan.Added)
+	if assert.Len(t, plan.Dropped, 1) {
+		assert.Equal(t, "legacy_id", plan.Dropped[0].Name)
+	}
+}
+
+func TestCollectionsPlanApply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAPIClient := mocks.NewMockAPIClientInterface(ctrl)
+
+	desired := desiredProductsSchema()
+	mockAPIClient.EXPECT().
+		GetCollectionWithResponse(gomock.Not(gomock.Nil()), "products").
+		Return(&api.GetCollectionResponse{JSON200: liveProductsCollection()}, nil).
+		Times(1)
+	mockAPIClient.EXPECT().
+		UpdateCollectionWithResponse(gomock.Not(gomock.Nil()), "products",
+			api.UpdateCollectionJSONRequestBody{
+				Fields: []api.Field{
+					{Name: "legacy_id", Drop: pointer.True()},
+					{Name: "price", Drop: pointer.True()},
+					{Name: "price", Type: "float"},
+					{Name: "in_stock", Type: "bool", Optional: pointer.True()},
+				},
+			}).
+		Return(&api.UpdateCollectionResponse{JSON200: &api.CollectionUpdateSchema{}}, nil).
+		Times(1)
+
+	client := NewClient(WithAPIClient(mockAPIClient))
+	plan, err := client.Collections().Plan(context.Background(), desired)
+	assert.NoError(t, err)
+	assert.NoError(t, plan.Apply(context.Background()))
+}
+
+func TestCollectionsPlanApplyRequiringReindexReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAPIClient := mocks.NewMockAPIClientInterface(ctrl)
+
+	desired := desiredProductsSchema()
+	desired.DefaultSortingField = pointer.String("price")
+	mockAPIClient.EXPECT().
+		GetCollectionWithResponse(gomock.Not(gomock.Nil()), "products").
+		Return(&api.GetCollectionResponse{JSON200: liveProductsCollection()}, nil).
+		Times(1)
+
+	client := NewClient(WithAPIClient(mockAPIClient))
+	plan, err := client.Collections().Plan(context.Background(), desired)
+	assert.NoError(t, err)
+
+	err = plan.Apply(context.Background())
+	assert.ErrorIs(t, err, ErrReindexRequired)
+	assert.EqualError(t, err, "schema change requires reindex: default_sorting_field cannot be changed")
+}
+
+func TestCollectionsPlanCreatesMissingCollection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAPIClient := mocks.NewMockAPIClientInterface(ctrl)
+
+	desired := desiredProductsSchema()
+	mockAPIClient.EXPECT().
+		GetCollectionWithResponse(gomock.Not(gomock.Nil()), "products").
+		Return(&api.GetCollectionResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+			Body:         []byte(`{"message": "Not Found"}`),
+		}, nil).
+		Times(1)
+	mockAPIClient.EXPECT().
+		CreateCollectionWithResponse(gomock.Not(gomock.Nil()), api.CreateCollectionJSONRequestBody(*desired)).
+		Return(&api.CreateCollectionResponse{JSON201: createNewCollection("products")}, nil).
+		Times(1)
+
+	client := NewClient(WithAPIClient(mockAPIClient))
+	plan, err := client.Collections().Plan(context.Background(), desired)
+	assert.NoError(t, err)
+	assert.True(t, plan.Create)
+	assert.True(t, plan.HasChanges())
+	assert.Contains(t, plan.String(), "create collection products:\n  + field title (string)\n")
+	assert.NoError(t, plan.Apply(context.Background()))
+}
+
+func TestCollectionsPlanOnHttpStatusErrorCodeReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAPIClient := mocks.NewMockAPIClientInterface(ctrl)
+
+	mockAPIClient.EXPECT().
+		GetCollectionWithResponse(gomock.Not(gomock.Nil()), "products").
+		Return(&api.GetCollectionResponse{
+			HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError},
+			Body:         []byte("Internal Server error"),
+		}, nil).
+		Times(1)
+
+	client := NewClient(WithAPIClient(mockAPIClient))
+	_, err := client.Collections().Plan(context.Background(), desiredProductsSchema())
+	assert.Error(t, err)
+}
+
+func TestSchemaPlanApplyWithoutClientReturnsError(t *testing.T) {
+	plan := PlanSchema(liveProductsCollection(), desiredProductsSchema())
+	assert.Error(t, plan.Apply(context.Background()))
+}
@@ -0,0 +1,255 @@
+package typesense
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+)
+
+func TestFieldsFromStructTypeMappings(t *testing.T) {
+	type nested struct {
+		City string `json:"city"`
+	}
+	type document struct {
+		String      string                 `json:"string"`
+		Bool        bool                   `json:"bool"`
+		Int         int                    `json:"int"`
+		Int8        int8                   `json:"int8"`
+		Int16       int16                  `json:"int16"`
+		Int32       int32                  `json:"int32"`
+		Int64       int64                  `json:"int64"`
+		Uint        uint                   `json:"uint"`
+		Uint8       uint8                  `json:"uint8"`
+		Uint16      uint16                 `json:"uint16"`
+		Uint32      uint32                 `json:"uint32"`
+		Uint64      uint64                 `json:"uint64"`
+		Float32     float32                `json:"float32"`
+		Float64     float64                `json:"float64"`
+		Time        time.Time              `json:"time"`
+		Geopoint    [2]float64             `json:"geopoint"`
+		Geopoint32  [2]float32             `json:"geopoint32"`
+		Struct      nested                 `json:"struct"`
+		Map         map[string]interface{} `json:"map"`
+		Interface   interface{}            `json:"interface"`
+		Bytes       []byte                 `json:"bytes"`
+		Strings     []string               `json:"strings"`
+		Bools       []bool                 `json:"bools"`
+		Int32s      []int32                `json:"int32s"`
+		Int64s      []int64                `json:"int64s"`
+		Floats      []float64              `json:"floats"`
+		Times       []time.Time            `json:"times"`
+		Geopoints   [][2]float64           `json:"geopoints"`
+		Structs     []nested               `json:"structs"`
+		Interfaces  []interface{}          `json:"interfaces"`
+		Array       [3]string              `json:"array"`
+		Pointer     *string                `json:"pointer"`
+		OmitEmpty   int32                  `json:"omit_empty,omitempty"`
+		NoJSONTag   string
+		Skipped     string    `json:"-"`
+		Ignored     string    `typesense:"-"`
+		unexported  string    //nolint:unused // exercises skipping of unexported fields
+		PointerList []*string `json:"pointer_list"`
+	}
+
+	fields, err := FieldsFromStruct[document]()
+	assert.NoError(t, err)
+	assert.Equal(t, []api.Field{
+		{Name: "string", Type: "string"},
+		{Name: "bool", Type: "bool"},
+		{Name: "int", Type: "int64"},
+		{Name: "int8", Type: "int32"},
+		{Name: "int16", Type: "int32"},
+		{Name: "int32", Type: "int32"},
+		{Name: "int64", Type: "int64"},
+		{Name: "uint", Type: "int64"},
+		{Name: "uint8", Type: "int32"},
+		{Name: "uint16", Type: "int32"},
+		{Name: "uint32", Type: "int64"},
+		{Name: "uint64", Type: "int64"},
+		{Name: "float32", Type: "float"},
+		{Name: "float64", Type: "float"},
+		{Name: "time", Type: "string"},
+		{Name: "geopoint", Type: "geopoint"},
+		{Name: "geopoint32", Type: "geopoint"},
+		{Name: "struct", Type: "object"},
+		{Name: "map", Type: "object"},
+		{Name: "interface", Type: "auto"},
+		{Name: "bytes", Type: "string"},
+		{Name: "strings", Type: "string[]"},
+		{Name: "bools", Type: "bool[]"},
+		{Name: "int32s", Type: "int32[]"},
+		{Name: "int64s", Type: "int64[]"},
+		{Name: "floats", Type: "float[]"},
+		{Name: "times", Type: "string[]"},
+		{Name: "geopoints", Type: "geopoint[]"},
+		{Name: "structs", Type: "object[]"},
+		{Name: "interfaces", Type: "auto"},
+		{Name: "array", Type: "string[]"},
+		{Name: "pointer", Type: "string", Optional: pointer.True()},
+		{Name: "omit_empty", Type: "int32", Optional: pointer.True()},
+		{Name: "NoJSONTag", Type: "string"},
+		{Name: "pointer_list", Type: "string[]"},
+	}, fields)
+}
+
+func TestFieldsFromStructOptions(t *testing.T) {
+	type document struct {
+		Title      string    `json:"title" typesense:"name,facet,sort,optional,index=false,locale=ja,infix"`
+		AuthorID   string    `json:"author_id" typesense:",reference=authors.id,async_reference"`
+		Body       string    `json:"body" typesense:",stem,stem_dictionary=plurals,store=false"`
+		Rating     float32   `json:"rating" typesense:",range_index,sort=false"`
+		Embedding  []float32 `json:"embedding" typesense:",embed_from=title|body"`
+		Embedding2 []float32 `json:"embedding2" typesense:",embed_model=openai/text-embedding-3-small,embed_from=title"`
+		Vector     []float32 `json:"vector" typesense:",num_dim=384,vec_dist=ip"`
+		Any        string    `json:"any" typesense:",type=auto"`
+	}
+
+	fields, err := FieldsFromStruct[*document]()
+	assert.NoError(t, err)
+
+	embedding := &api.FieldEmbed{From: []string{"title", "body"}}
+	embedding.ModelConfig.ModelName = DefaultEmbeddingModel
+	embedding2 := &api.FieldEmbed{From: []string{"title"}}
+	embedding2.ModelConfig.ModelName = "openai/text-embedding-3-small"
+
+	assert.Equal(t, []api.Field{
+		{
+			Name:     "name",
+			Type:     "string",
+			Facet:    pointer.True(),
+			Sort:     pointer.True(),
+			Optional: pointer.True(),
+			Index:    pointer.False(),
+			Locale:   pointer.String("ja"),
+			Infix:    pointer.True(),
+		},
+		{Name: "author_id", Type: "string", Reference: pointer.String("authors.id"), AsyncReference: pointer.True()},
+		{Name: "body", Type: "string", Stem: pointer.True(), StemDictionary: pointer.String("plurals"), Store: pointer.False()},
+		{Name: "rating", Type: "float", RangeIndex: pointer.True(), Sort: pointer.False()},
+		{Name: "embedding", Type: "float[]", Embed: embedding},
+		{Name: "embedding2", Type: "float[]", Embed: embedding2},
+		{Name: "vector", Type: "float[]", NumDim: pointer.Int(384), VecDist: pointer.String("ip")},
+		{Name: "any", Type: "auto"},
+	}, fields)
+}
+
+func TestSchemaFromStructWithNestedAndEmbeddedStructs(t *testing.T) {
+	type address struct {
+		City    string `json:"city" typesense:",facet"`
+		Country string `json:"country"`
+	}
+	type base struct {
+		ID        string `json:"id"`
+		CreatedAt int64  `json:"created_at" typesense:",default_sort"`
+	}
+	type document struct {
+		base
+		Address   address   `json:"address"`
+		Addresses []address `json:"addresses" typesense:",optional"`
+		Location  *address  `json:"location"`
+	}
+
+	schema, err := SchemaFromStruct[document]("companies")
+	assert.NoError(t, err)
+	assert.Equal(t, &api.CollectionSchema{
+		Name:                "companies",
+		DefaultSortingField: pointer.String("created_at"),
+		EnableNestedFields:  pointer.True(),
+		Fields: []api.Field{
+			{Name: "id", Type: "string"},
+			{Name: "created_at", Type: "int64"},
+			{Name: "address", Type: "object"},
+			{Name: "address.city", Type: "string", Facet: pointer.True()},
+			{Name: "addresses", Type: "object[]", Optional: pointer.True()},
+			{Name: "addresses.city", Type: "string[]", Facet: pointer.True()},
+			{Name: "location", Type: "object", Optional: pointer.True()},
+			{Name: "location.city", Type: "string", Facet: pointer.True()},
+		},
+	}, schema)
+}
+
+func TestSchemaFromStructErrors(t *testing.T) {
+	_, err := SchemaFromStruct[string]("strings")
+	assert.EqualError(t, err, "cannot derive schema from string: not a struct")
+
+	type unknownOption struct {
+		Title string `typesense:",facets"`
+	}
+	_, err = FieldsFromStruct[unknownOption]()
+	assert.EqualError(t, err, `invalid typesense tag of field Title: unknown option "facets"`)
+
+	type invalidBool struct {
+		Title string `typesense:",facet=maybe"`
+	}
+	_, err = FieldsFromStruct[invalidBool]()
+	assert.ErrorContains(t, err, "invalid typesense tag of field Title: option facet")
+
+	type missingValue struct {
+		Title string `typesense:",locale"`
+	}
+	_, err = FieldsFromStruct[missingValue]()
+	assert.EqualError(t, err, "invalid typesense tag of field Title: option locale requires a value")
+
+	type invalidNumDim struct {
+		Vector []float32 `typesense:",num_dim=many"`
+	}
+	_, err = FieldsFromStruct[invalidNumDim]()
+	assert.ErrorContains(t, err, "invalid typesense tag of field Vector: option num_dim")
+
+	type embedModelOnly struct {
+		Embedding []float32 `typesense:",embed_model=ts/e5-small"`
+	}
+	_, err = FieldsFromStruct[embedModelOnly]()
+	assert.EqualError(t, err, "invalid typesense tag of field Embedding: option embed_model requires embed_from")
+
+	type unsupportedType struct {
+		Channel chan int
+	}
+	_, err = FieldsFromStruct[unsupportedType]()
+	assert.EqualError(t, err, "cannot derive type of field Channel: unsupported type chan int")
+
+	type nestedArrays struct {
+		Matrix [][]int32
+	}
+	_, err = FieldsFromStruct[nestedArrays]()
+	assert.EqualError(t, err, "cannot derive type of field Matrix: nested arrays of []int32 are not supported")
+}
+
+type schemaCat struct {
+	Name   string     `json:"name"`
+	Parent *schemaCat `json:"parent" typesense:",optional"`
+}
+
+type schemaAuthor struct {
+	Name  string       `json:"name" typesense:""`
+	Books []schemaBook `json:"books" typesense:",optional"`
+}
+
+type schemaBook struct {
+	Title  string        `json:"title" typesense:""`
+	Author *schemaAuthor `json:"author" typesense:""`
+}
+
+type schemaCategory struct {
+	Name   string          `json:"name"`
+	Parent *schemaCategory `json:"parent"`
+}
+
+func TestSchemaFromStructWithCyclicType(t *testing.T) {
+	_, err := FieldsFromStruct[schemaCat]()
+	assert.EqualError(t, err, "cannot derive fields of field Parent: type typesense.schemaCat contains itself")
+
+	_, err = FieldsFromStruct[schemaAuthor]()
+	assert.EqualError(t, err, "cannot derive fields of field Books: type typesense.schemaBook contains itself")
+
+	// the nested fields are only derived from tagged fields, so the cycle ends
+	fields, err := FieldsFromStruct[schemaCategory]()
+	assert.NoError(t, err)
+	assert.Equal(t, []api.Field{
+		{Name: "name", Type: "string"},
+		{Name: "parent", Type: "object", Optional: pointer.True()},
+	}, fields)
+}
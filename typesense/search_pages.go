@@ -0,0 +1,248 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"strings"
+
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+)
+
+// MaxSearchPerPage is the maximum number of hits Typesense returns per page.
+const MaxSearchPerPage = 250
+
+// SearchOption configures how TypedDocumentsInterface.Pages and SearchAll walk
+// through the pages of a search.
+type SearchOption func(*searchConfig)
+
+type searchConfig struct {
+	maxHits     int
+	limitHits   int
+	keysetField string
+}
+
+// WithSearchMaxHits stops the walk once n hits have been fetched.
+func WithSearchMaxHits(n int) SearchOption {
+	return func(c *searchConfig) {
+		c.maxHits = n
+	}
+}
+
+// WithSearchLimitHits sets the limit_hits of the API key, i.e. the maximum
+// number of hits the server allows to be fetched through pagination. The walk
+// stops at this limit instead of failing with an error, unless it is a keyset
+// walk, which is not limited by it.
+func WithSearchLimitHits(n int) SearchOption {
+	return func(c *searchConfig) {
+		c.limitHits = n
+	}
+}
+
+// WithSearchKeyset walks through the hits by sorting them by field and fetching
+// the hits after the last one of the previous page with filter_by, instead of
+// by offset. This goes beyond the deep pagination limits of offsets, but
+// requires a numeric field with unique values. The sort_by of the search must be
+// empty, which sorts ascending, or field:asc or field:desc. The page and offset
+// of the search are ignored, and grouped searches yield an error.
+func WithSearchKeyset(field string) SearchOption {
+	return func(c *searchConfig) {
+		c.keysetField = field
+	}
+}
+
+func (d *typedDocuments[T]) Pages(ctx context.Context, params *api.SearchCollectionParams, opts ...SearchOption) iter.Seq2[*SearchResult[T], error] {
+	return func(yield func(*SearchResult[T], error) bool) {
+		config := &searchConfig{}
+		for _, opt := range opts {
+			opt(config)
+		}
+		walk, err := newSearchWalk(params, config)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for {
+			pageParams, ok := walk.next()
+			if !ok {
+				return
+			}
+			result, last, err := d.searchPage(ctx, pageParams, config.keysetField)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(result, nil) || !walk.advance(len(result.Hits)+len(result.GroupedHits), result.Found, last) {
+				return
+			}
+		}
+	}
+}
+
+func (d *typedDocuments[T]) SearchAll(ctx context.Context, params *api.SearchCollectionParams, opts ...SearchOption) iter.Seq2[Hit[T], error] {
+	return func(yield func(Hit[T], error) bool) {
+		for page, err := range d.Pages(ctx, params, opts...) {
+			if err != nil {
+				yield(Hit[T]{}, err)
+				return
+			}
+			for _, hit := range page.Hits {
+				if !yield(hit, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// searchPage searches and, for a keyset walk, returns the value of the keyset
+// field of the last hit.
+func (d *typedDocuments[T]) searchPage(ctx context.Context, params *api.SearchCollectionParams, keysetField string) (*SearchResult[T], string, error) {
+	if keysetField == "" {
+		result, err := d.Search(ctx, params)
+		return result, "", err
+	}
+
+	response, err := d.apiClient.SearchCollection(ctx, d.collectionName, params)
+	if err != nil {
+		return nil, "", err
+	}
+	var body json.RawMessage
+	if err := decodeJSONResponse(response, http.StatusOK, &body); err != nil {
+		return nil, "", err
+	}
+	result, err := DecodeSearchResult[T](body)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(result.Hits) == 0 {
+		return result, "", nil
+	}
+
+	var keys struct {
+		Hits []struct {
+			Document map[string]json.RawMessage `json:"document"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return nil, "", fmt.Errorf("failed to decode search result: %w", err)
+	}
+	value := keys.Hits[len(keys.Hits)-1].Document[keysetField]
+	// a json.Number also accepts a string holding a number, which cannot be
+	// compared in filter_by
+	var number json.Number
+	if err := json.Unmarshal(value, &number); err != nil || value[0] == '"' {
+		return nil, "", fmt.Errorf("keyset field %s of the last hit is not a number: %s", keysetField, value)
+	}
+	return result, number.String(), nil
+}
+
+// searchWalk computes the parameters of the pages of a search.
+type searchWalk struct {
+	params  api.SearchCollectionParams
+	config  *searchConfig
+	perPage int
+	offset  int
+	fetched int
+	limit   int
+
+	// keysetOp and after are the comparison and the value of the keyset field
+	// selecting the hits after the previous page.
+	keysetOp string
+	after    string
+}
+
+func newSearchWalk(params *api.SearchCollectionParams, config *searchConfig) (*searchWalk, error) {
+	w := &searchWalk{config: config, perPage: MaxSearchPerPage}
+	if params != nil {
+		w.params = *params
+	}
+	switch {
+	case w.params.Limit != nil:
+		w.perPage = *w.params.Limit
+	case w.params.PerPage != nil:
+		w.perPage = *w.params.PerPage
+	}
+	if w.perPage <= 0 || w.perPage > MaxSearchPerPage {
+		w.perPage = MaxSearchPerPage
+	}
+	switch {
+	case w.params.Offset != nil:
+		w.offset = *w.params.Offset
+	case w.params.Page != nil && *w.params.Page > 1:
+		w.offset = (*w.params.Page - 1) * w.perPage
+	}
+	w.params.Page, w.params.PerPage = nil, nil
+
+	if config.keysetField == "" {
+		return w, nil
+	}
+	if w.params.GroupBy != nil && *w.params.GroupBy != "" {
+		return nil, errors.New("keyset pagination does not support grouped searches")
+	}
+	w.offset = 0
+	w.keysetOp = ">"
+	sortBy := ""
+	if w.params.SortBy != nil {
+		sortBy = strings.ReplaceAll(*w.params.SortBy, " ", "")
+	}
+	field, direction, _ := strings.Cut(sortBy, ":")
+	switch {
+	case sortBy == "", field == config.keysetField && strings.EqualFold(direction, "asc"):
+		w.params.SortBy = pointer.String(config.keysetField + ":asc")
+	case field == config.keysetField && strings.EqualFold(direction, "desc"):
+		w.keysetOp = "<"
+	default:
+		return nil, errors.New("keyset pagination requires sort_by to be " + config.keysetField + ":asc or " + config.keysetField + ":desc")
+	}
+	return w, nil
+}
+
+// next returns the parameters of the next page and reports whether there is one.
+func (w *searchWalk) next() (*api.SearchCollectionParams, bool) {
+	w.limit = w.perPage
+	if w.config.maxHits > 0 {
+		w.limit = min(w.limit, w.config.maxHits-w.fetched)
+	}
+	if w.config.limitHits > 0 {
+		if w.config.keysetField == "" {
+			w.limit = min(w.limit, w.config.limitHits-w.offset)
+		} else {
+			w.limit = min(w.limit, w.config.limitHits)
+		}
+	}
+	if w.limit <= 0 {
+		return nil, false
+	}
+
+	params := w.params
+	params.Offset, params.Limit = pointer.Int(w.offset), pointer.Int(w.limit)
+	if w.after != "" {
+		filter := w.config.keysetField + ":" + w.keysetOp + w.after
+		if w.params.FilterBy != nil && *w.params.FilterBy != "" {
+			filter = "(" + *w.params.FilterBy + ") && " + filter
+		}
+		params.FilterBy = &filter
+	}
+	return &params, true
+}
+
+// advance moves past a page with the given number of hits or groups and
+// reports whether there may be more pages.
+func (w *searchWalk) advance(hits int, found *int, last string) bool {
+	w.fetched += hits
+	if hits < w.limit {
+		return false
+	}
+	if w.config.keysetField != "" {
+		w.after = last
+		return last != ""
+	}
+	w.offset += hits
+	return found == nil || w.offset < *found
+}
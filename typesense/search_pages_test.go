@@ -0,0 +1,267 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+)
+
+var keysetFilterPattern = regexp.MustCompile(`num_employees:([<>])(\d+)$`)
+
+// newSearchPagesTestServer returns a client searching a collection of n
+// companies with 10, 20, 30... employees. The server honors offset, limit,
+// sort_by on num_employees, keyset filters on num_employees and limitHits, and
+// records the query of every search.
+func newSearchPagesTestServer(t *testing.T, n int, limitHits int) (*Client, func() []url.Values) {
+	var companies []companyDocument
+	for i := 1; i <= n; i++ {
+		companies = append(companies, companyDocument{ID: strconv.Itoa(i), CompanyName: "Company " + strconv.Itoa(i), NumEmployees: i * 10})
+	}
+
+	recordQuery := func(r *http.Request) url.Values {
+		validateRequestMetadata(t, r, "/collections/companies/documents/search", http.MethodGet)
+		return r.URL.Query()
+	}
+	return newRecordingTestClient(t, recordQuery, func(w http.ResponseWriter, _ *http.Request, _ int, query url.Values) {
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		if limitHits > 0 && offset+limit > limitHits {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message": "Only upto ` + strconv.Itoa(limitHits) + ` hits can be fetched."}`))
+			return
+		}
+
+		matches := slices.Clone(companies)
+		if strings.EqualFold(query.Get("sort_by"), "num_employees:desc") {
+			slices.Reverse(matches)
+		}
+		if match := keysetFilterPattern.FindStringSubmatch(query.Get("filter_by")); match != nil {
+			after, _ := strconv.Atoi(match[2])
+			matches = slices.DeleteFunc(matches, func(c companyDocument) bool {
+				return (match[1] == ">" && c.NumEmployees <= after) || (match[1] == "<" && c.NumEmployees >= after)
+			})
+		}
+
+		hits := []map[string]any{}
+		for _, company := range matches[min(offset, len(matches)):min(offset+limit, len(matches))] {
+			hits = append(hits, map[string]any{"document": company})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"found": len(matches), "hits": hits})
+	})
+}
+
+func collectSearchIDs(t *testing.T, hits func(yield func(Hit[companyDocument], error) bool)) []string {
+	var ids []string
+	for hit, err := range hits {
+		assert.NoError(t, err)
+		ids = append(ids, hit.Document.ID)
+	}
+	return ids
+}
+
+func idRange(from, to int) []string {
+	var ids []string
+	for i := from; i <= to; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	return ids
+}
+
+func TestTypedDocumentsSearchAllWalksAllPages(t *testing.T) {
+	client, queries := newSearchPagesTestServer(t, 7, 0)
+	params := &api.SearchCollectionParams{Q: pointer.String("*"), PerPage: pointer.Int(3)}
+
+	ids := collectSearchIDs(t, GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		SearchAll(context.Background(), params))
+
+	assert.Equal(t, idRange(1, 7), ids)
+	var pages [][2]string
+	for _, query := range queries() {
+		assert.Empty(t, query.Get("page"))
+		assert.Empty(t, query.Get("per_page"))
+		pages = append(pages, [2]string{query.Get("offset"), query.Get("limit")})
+	}
+	assert.Equal(t, [][2]string{{"0", "3"}, {"3", "3"}, {"6", "3"}}, pages)
+	assert.Equal(t, pointer.Int(3), params.PerPage)
+	assert.Nil(t, params.Offset)
+}
+
+func TestTypedDocumentsPagesStopsAtFound(t *testing.T) {
+	client, queries := newSearchPagesTestServer(t, 6, 0)
+
+	var pages []int
+	for page, err := range GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		Pages(context.Background(), &api.SearchCollectionParams{Q: pointer.String("*"), PerPage: pointer.Int(3)}) {
+		assert.NoError(t, err)
+		pages = append(pages, len(page.Hits))
+	}
+
+	assert.Equal(t, []int{3, 3}, pages)
+	assert.Len(t, queries(), 2)
+}
+
+func TestTypedDocumentsSearchAllStartsAtPage(t *testing.T) {
+	client, _ := newSearchPagesTestServer(t, 7, 0)
+	params := &api.SearchCollectionParams{Q: pointer.String("*"), Page: pointer.Int(2), PerPage: pointer.Int(3)}
+
+	ids := collectSearchIDs(t, GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		SearchAll(context.Background(), params))
+
+	assert.Equal(t, idRange(4, 7), ids)
+}
+
+func TestTypedDocumentsSearchAllWithMaxHits(t *testing.T) {
+	client, queries := newSearchPagesTestServer(t, 10, 0)
+	params := &api.SearchCollectionParams{Q: pointer.String("*"), PerPage: pointer.Int(3)}
+
+	ids := collectSearchIDs(t, GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		SearchAll(context.Background(), params, WithSearchMaxHits(5)))
+
+	assert.Equal(t, idRange(1, 5), ids)
+	assert.Equal(t, "2", queries()[1].Get("limit"))
+}
+
+func TestTypedDocumentsSearchAllStopsAtLimitHits(t *testing.T) {
+	client, queries := newSearchPagesTestServer(t, 10, 5)
+	params := &api.SearchCollectionParams{Q: pointer.String("*"), PerPage: pointer.Int(3)}
+
+	ids := collectSearchIDs(t, GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		SearchAll(context.Background(), params, WithSearchLimitHits(5)))
+
+	assert.Equal(t, idRange(1, 5), ids)
+	assert.Len(t, queries(), 2)
+}
+
+func TestTypedDocumentsSearchAllWithKeyset(t *testing.T) {
+	client, queries := newSearchPagesTestServer(t, 10, 3)
+	params := &api.SearchCollectionParams{Q: pointer.String("*"), FilterBy: pointer.String("company_name:Company")}
+
+	ids := collectSearchIDs(t, GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		SearchAll(context.Background(), params, WithSearchKeyset("num_employees"), WithSearchLimitHits(3)))
+
+	assert.Equal(t, idRange(1, 10), ids)
+	var filters []string
+	for _, query := range queries() {
+		assert.Equal(t, "num_employees:asc", query.Get("sort_by"))
+		assert.Equal(t, "0", query.Get("offset"))
+		filters = append(filters, query.Get("filter_by"))
+	}
+	assert.Equal(t, []string{
+		"company_name:Company",
+		"(company_name:Company) && num_employees:>30",
+		"(company_name:Company) && num_employees:>60",
+		"(company_name:Company) && num_employees:>90",
+	}, filters)
+}
+
+func TestTypedDocumentsSearchAllWithDescendingKeyset(t *testing.T) {
+	client, queries := newSearchPagesTestServer(t, 5, 0)
+	params := &api.SearchCollectionParams{Q: pointer.String("*"), SortBy: pointer.String("num_employees:DESC"), PerPage: pointer.Int(2)}
+
+	ids := collectSearchIDs(t, GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		SearchAll(context.Background(), params, WithSearchKeyset("num_employees")))
+
+	assert.Equal(t, []string{"5", "4", "3", "2", "1"}, ids)
+	assert.Equal(t, "num_employees:<40", queries()[1].Get("filter_by"))
+}
+
+func TestTypedDocumentsSearchAllWithKeysetRequiresSortByKeysetField(t *testing.T) {
+	client, queries := newSearchPagesTestServer(t, 5, 0)
+	params := &api.SearchCollectionParams{Q: pointer.String("*"), SortBy: pointer.String("_text_match:desc")}
+
+	var errs []error
+	for _, err := range GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		SearchAll(context.Background(), params, WithSearchKeyset("num_employees")) {
+		errs = append(errs, err)
+	}
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "keyset pagination requires sort_by to be num_employees:asc or num_employees:desc")
+	assert.Empty(t, queries())
+}
+
+func TestTypedDocumentsPagesWithKeysetRejectsGroupedSearch(t *testing.T) {
+	client, queries := newSearchPagesTestServer(t, 5, 0)
+	params := &api.SearchCollectionParams{Q: pointer.String("*"), GroupBy: pointer.String("company_name")}
+
+	var errs []error
+	for _, err := range GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		Pages(context.Background(), params, WithSearchKeyset("num_employees")) {
+		errs = append(errs, err)
+	}
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "keyset pagination does not support grouped searches")
+	assert.Empty(t, queries())
+}
+
+func TestTypedDocumentsSearchAllWithKeysetOnStringFieldYieldsError(t *testing.T) {
+	client, _ := newSearchPagesTestServer(t, 5, 0)
+	params := &api.SearchCollectionParams{Q: pointer.String("*"), SortBy: pointer.String("id:asc")}
+
+	var ids []string
+	var errs []error
+	for hit, err := range GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		SearchAll(context.Background(), params, WithSearchKeyset("id")) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, hit.Document.ID)
+	}
+
+	assert.Empty(t, ids)
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "keyset field id of the last hit is not a number")
+}
+
+func TestTypedDocumentsSearchAllOnHttpStatusErrorCodeYieldsError(t *testing.T) {
+	client, _ := newSearchPagesTestServer(t, 10, 5)
+	params := &api.SearchCollectionParams{Q: pointer.String("*"), PerPage: pointer.Int(3)}
+
+	var ids []string
+	var errs []error
+	for hit, err := range GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		SearchAll(context.Background(), params) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, hit.Document.ID)
+	}
+
+	assert.Equal(t, idRange(1, 3), ids)
+	assert.Len(t, errs, 1)
+	var httpErr *HTTPError
+	assert.ErrorAs(t, errs[0], &httpErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, httpErr.Status)
+}
+
+func TestTypedDocumentsSearchAllStopsWhenBreakingOutOfTheLoop(t *testing.T) {
+	client, queries := newSearchPagesTestServer(t, 10, 0)
+	params := &api.SearchCollectionParams{Q: pointer.String("*"), PerPage: pointer.Int(3)}
+
+	var ids []string
+	for hit, err := range GenericCollection[companyDocument](client, "companies").TypedDocuments().
+		SearchAll(context.Background(), params) {
+		assert.NoError(t, err)
+		ids = append(ids, hit.Document.ID)
+		if len(ids) == 4 {
+			break
+		}
+	}
+
+	assert.Equal(t, idRange(1, 4), ids)
+	assert.Len(t, queries(), 2)
+}
@@ -0,0 +1,112 @@
+package typesense
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/typesense/typesense-go/v4/typesense/api"
+)
+
+// SearchResult is a search result whose hits are decoded into documents of type T.
+// The other fields of api.SearchResult, e.g. Found, are promoted from the embedded struct.
+type SearchResult[T any] struct {
+	api.SearchResult
+	// Hits and GroupedHits shadow the hits of the embedded api.SearchResult, which are left empty.
+	Hits        []Hit[T]        `json:"hits"`
+	GroupedHits []GroupedHit[T] `json:"grouped_hits,omitempty"`
+}
+
+// Hit is a search hit whose document is decoded into T. The other fields of
+// api.SearchResultHit, e.g. TextMatchInfo, GeoDistanceMeters, VectorDistance and
+// HybridSearchInfo, are promoted from the embedded struct.
+type Hit[T any] struct {
+	api.SearchResultHit
+	// Document shadows the document of the embedded api.SearchResultHit, which is left empty.
+	Document T `json:"document"`
+	// Highlight is the highlighted version of the matching document, see DecodeHighlight.
+	Highlight json.RawMessage `json:"highlight,omitempty"`
+}
+
+// GroupedHit is a group of search hits whose documents are decoded into T.
+type GroupedHit[T any] struct {
+	api.SearchGroupedHit
+	// Hits shadows the hits of the embedded api.SearchGroupedHit, which are left empty.
+	Hits []Hit[T] `json:"hits"`
+}
+
+// HighlightField is the highlight of a string field. The highlight of a string
+// array field is a list of HighlightField, one for every matching item.
+type HighlightField struct {
+	MatchedTokens []string `json:"matched_tokens"`
+	Snippet       string   `json:"snippet"`
+	// Value is the full value of the field with highlighting, which is only
+	// present when the field is listed in highlight_full_fields.
+	Value string `json:"value,omitempty"`
+}
+
+// DecodeHighlight decodes the highlighted version of the matching document into v,
+// which usually mirrors the shape of the document with HighlightField values, e.g.
+//
+//	var highlight struct {
+//		CompanyName *typesense.HighlightField  `json:"company_name"`
+//		Tags        []typesense.HighlightField `json:"tags"`
+//	}
+//	err := hit.DecodeHighlight(&highlight)
+func (h *Hit[T]) DecodeHighlight(v any) error {
+	if len(h.Highlight) == 0 {
+		return nil
+	}
+	return json.Unmarshal(h.Highlight, v)
+}
+
+// GeoDistance returns the distance in meters between the geopoint field of the
+// matching document and the point the search was sorted by.
+func (h *Hit[T]) GeoDistance(field string) (int, bool) {
+	if h.GeoDistanceMeters == nil {
+		return 0, false
+	}
+	meters, ok := (*h.GeoDistanceMeters)[field]
+	return meters, ok
+}
+
+// DecodeSearchResult decodes the body of a search response, e.g. a union multi
+// search response, into a SearchResult whose documents are decoded into T.
+func DecodeSearchResult[T any](body []byte) (*SearchResult[T], error) {
+	var result SearchResult[T]
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode search result: %w", err)
+	}
+	return &result, nil
+}
+
+// DecodeHits decodes the hits of the body of a search response into documents of type T.
+func DecodeHits[T any](body []byte) ([]Hit[T], error) {
+	result, err := DecodeSearchResult[T](body)
+	if err != nil {
+		return nil, err
+	}
+	return result.Hits, nil
+}
+
+// UnionHits returns the hits of a union multi search that matched the search
+// with the given index, decoding their documents into T. Since the searches of
+// a union may target collections with different document types, the result is
+// decoded with json.RawMessage documents first:
+//
+//	result, err := typesense.DecodeSearchResult[json.RawMessage](response.Body)
+//	books, err := typesense.UnionHits[book](result.Hits, 0)
+//	authors, err := typesense.UnionHits[author](result.Hits, 1)
+func UnionHits[T any](hits []Hit[json.RawMessage], searchIndex int) ([]Hit[T], error) {
+	var result []Hit[T]
+	for i, hit := range hits {
+		if hit.SearchIndex == nil || *hit.SearchIndex != searchIndex {
+			continue
+		}
+		typedHit := Hit[T]{SearchResultHit: hit.SearchResultHit, Highlight: hit.Highlight}
+		if err := json.Unmarshal(hit.Document, &typedHit.Document); err != nil {
+			return result, fmt.Errorf("failed to decode document of hit %d: %w", i, err)
+		}
+		result = append(result, typedHit)
+	}
+	return result, nil
+}
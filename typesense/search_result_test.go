@@ -0,0 +1,131 @@
+package typesense
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+)
+
+type bookDocument struct {
+	ID    string   `json:"id"`
+	Title string   `json:"title"`
+	Tags  []string `json:"tags"`
+}
+
+type authorDocument struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDecodeSearchResultWithHighlightAndDistances(t *testing.T) {
+	body := []byte(`{
+		"found": 1,
+		"hits": [{
+			"document": {"id": "1", "title": "Dune", "tags": ["sci-fi", "classic"]},
+			"highlight": {
+				"title": {"matched_tokens": ["Dune"], "snippet": "<mark>Dune</mark>"},
+				"tags": [{"matched_tokens": [], "snippet": "sci-fi"}, {"matched_tokens": ["classic"], "snippet": "<mark>classic</mark>"}]
+			},
+			"geo_distance_meters": {"location": 1020},
+			"vector_distance": 0.25,
+			"hybrid_search_info": {"rank_fusion_score": 0.75}
+		}]
+	}`)
+
+	result, err := DecodeSearchResult[bookDocument](body)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *result.Found)
+	assert.Len(t, result.Hits, 1)
+
+	hit := result.Hits[0]
+	assert.Equal(t, bookDocument{ID: "1", Title: "Dune", Tags: []string{"sci-fi", "classic"}}, hit.Document)
+
+	var highlight struct {
+		Title *HighlightField  `json:"title"`
+		Tags  []HighlightField `json:"tags"`
+	}
+	assert.NoError(t, hit.DecodeHighlight(&highlight))
+	assert.Equal(t, &HighlightField{MatchedTokens: []string{"Dune"}, Snippet: "<mark>Dune</mark>"}, highlight.Title)
+	assert.Equal(t, []HighlightField{
+		{MatchedTokens: []string{}, Snippet: "sci-fi"},
+		{MatchedTokens: []string{"classic"}, Snippet: "<mark>classic</mark>"},
+	}, highlight.Tags)
+
+	meters, ok := hit.GeoDistance("location")
+	assert.True(t, ok)
+	assert.Equal(t, 1020, meters)
+	_, ok = hit.GeoDistance("other")
+	assert.False(t, ok)
+	assert.Equal(t, float32(0.25), *hit.VectorDistance)
+	assert.Equal(t, float32(0.75), *hit.HybridSearchInfo.RankFusionScore)
+}
+
+func TestDecodeSearchResultWithGroupedHits(t *testing.T) {
+	body := []byte(`{
+		"found": 2,
+		"grouped_hits": [
+			{"found": 1, "group_key": ["sci-fi"], "hits": [{"document": {"id": "1", "title": "Dune"}}]},
+			{"found": 1, "group_key": ["fantasy"], "hits": [{"document": {"id": "2", "title": "The Hobbit"}}]}
+		]
+	}`)
+
+	result, err := DecodeSearchResult[bookDocument](body)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Hits)
+	assert.Nil(t, result.SearchResult.GroupedHits)
+	assert.Len(t, result.GroupedHits, 2)
+	assert.Equal(t, []interface{}{"fantasy"}, result.GroupedHits[1].GroupKey)
+	assert.Equal(t, 1, *result.GroupedHits[1].Found)
+	assert.Nil(t, result.GroupedHits[1].SearchGroupedHit.Hits)
+	assert.Equal(t, []Hit[bookDocument]{{Document: bookDocument{ID: "2", Title: "The Hobbit"}}}, result.GroupedHits[1].Hits)
+}
+
+func TestDecodeHits(t *testing.T) {
+	hits, err := DecodeHits[*bookDocument]([]byte(`{"hits": [{"document": {"id": "1", "title": "Dune"}, "text_match": 100}]}`))
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, &bookDocument{ID: "1", Title: "Dune"}, hits[0].Document)
+	assert.Equal(t, int64(100), *hits[0].TextMatch)
+
+	_, err = DecodeHits[bookDocument]([]byte(`{"hits": [{"document": {"id": 1}}]}`))
+	assert.ErrorContains(t, err, "failed to decode search result")
+}
+
+func TestUnionHits(t *testing.T) {
+	body := []byte(`{
+		"found": 3,
+		"hits": [
+			{"document": {"id": "1", "title": "Dune"}, "search_index": 0},
+			{"document": {"id": "a", "name": "Frank Herbert"}, "search_index": 1, "highlight": {"name": {"matched_tokens": ["Frank"], "snippet": "<mark>Frank</mark> Herbert"}}},
+			{"document": {"id": "2", "title": "Children of Dune"}, "search_index": 0}
+		],
+		"union_request_params": [{"collection_name": "books", "per_page": 10, "q": "dune"}, {"collection_name": "authors", "per_page": 10, "q": "frank"}]
+	}`)
+
+	result, err := DecodeSearchResult[json.RawMessage](body)
+	assert.NoError(t, err)
+	assert.Len(t, *result.UnionRequestParams, 2)
+
+	books, err := UnionHits[bookDocument](result.Hits, 0)
+	assert.NoError(t, err)
+	assert.Len(t, books, 2)
+	assert.Equal(t, bookDocument{ID: "1", Title: "Dune"}, books[0].Document)
+	assert.Equal(t, bookDocument{ID: "2", Title: "Children of Dune"}, books[1].Document)
+
+	authors, err := UnionHits[authorDocument](result.Hits, 1)
+	assert.NoError(t, err)
+	assert.Len(t, authors, 1)
+	assert.Equal(t, authorDocument{ID: "a", Name: "Frank Herbert"}, authors[0].Document)
+	assert.Equal(t, 1, *authors[0].SearchIndex)
+	var highlight map[string]HighlightField
+	assert.NoError(t, authors[0].DecodeHighlight(&highlight))
+	assert.Equal(t, "<mark>Frank</mark> Herbert", highlight["name"].Snippet)
+
+	_, err = UnionHits[authorDocument]([]Hit[json.RawMessage]{{
+		SearchResultHit: api.SearchResultHit{SearchIndex: new(int)},
+		Document:        json.RawMessage(`{"id": 1}`),
+	}}, 0)
+	assert.ErrorContains(t, err, "failed to decode document of hit 0")
+}
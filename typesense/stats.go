@@ -20,7 +20,7 @@ func (s *stats) Retrieve(ctx context.Context) (*api.APIStatsResponse, error) {
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -60,7 +60,7 @@ func (s *stemmingDictionaries) UpsertJsonl(ctx context.Context, dictionaryId str
 	if response.StatusCode != http.StatusOK {
 		defer response.Body.Close()
 		body, _ := io.ReadAll(response.Body)
-		return nil, &HTTPError{Status: response.StatusCode, Body: body}
+		return nil, newHTTPError(response, body)
 	}
 	return response.Body, nil
 }
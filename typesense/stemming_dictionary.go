@@ -21,7 +21,7 @@ func (s *stemmingDictionary) Retrieve(ctx context.Context) (*api.StemmingDiction
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
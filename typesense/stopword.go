@@ -24,7 +24,7 @@ func (s *stopword) Retrieve(ctx context.Context) (*api.StopwordsSetSchema, error
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return &response.JSON200.Stopwords, nil
 }
@@ -37,7 +37,7 @@ func (s *stopword) Delete(ctx context.Context) (*struct {
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
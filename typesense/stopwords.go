@@ -21,7 +21,7 @@ func (s *stopwords) Retrieve(ctx context.Context) ([]api.StopwordsSetSchema, err
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200.Stopwords, nil
 }
@@ -32,7 +32,7 @@ func (s *stopwords) Upsert(ctx context.Context, stopwordsSetId string, stopwords
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -28,7 +28,7 @@ func (s *synonymSet) Retrieve(ctx context.Context) (*api.SynonymSetSchema, error
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -39,7 +39,7 @@ func (s *synonymSet) Upsert(ctx context.Context, synonymSetSchema *api.SynonymSe
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -50,7 +50,7 @@ func (s *synonymSet) Delete(ctx context.Context) (*api.SynonymSetDeleteSchema, e
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
@@ -27,7 +27,7 @@ func (s *synonymSets) Upsert(ctx context.Context, synonymSetName string, synonym
 		return nil, err
 	}
 	if response.JSON200 == nil {
-		return nil, &HTTPError{Status: response.StatusCode(), Body: response.Body}
+		return nil, newHTTPError(response.HTTPResponse, response.Body)
 	}
 	return response.JSON200, nil
 }
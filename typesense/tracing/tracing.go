@@ -0,0 +1,248 @@
+// Package tracing traces the requests made by a typesense.Client with OpenTelemetry.
+//
+//	client := typesense.NewClient(
+//		typesense.WithNodes(nodes),
+//		typesense.WithAPIKey("<API_KEY>"),
+//		tracing.WithTracing(),
+//	)
+//
+// Every call made by the client is traced with a span named after its operation,
+// e.g. "typesense.documents.search", which is a child of the span in the context
+// passed to the client. Every attempt sent to a node is traced with a
+// "typesense.attempt" child span.
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/typesense/typesense-go/v4/typesense"
+	"github.com/typesense/typesense-go/v4/typesense/api/circuit"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/typesense/typesense-go/v4/typesense/tracing"
+
+const (
+	attrOperation    = attribute.Key("db.operation.name")
+	attrCollection   = attribute.Key("db.collection.name")
+	attrQueryBy      = attribute.Key("typesense.query_by")
+	attrFound        = attribute.Key("typesense.found")
+	attrHits         = attribute.Key("typesense.hits")
+	attrSearchTimeMs = attribute.Key("typesense.search_time_ms")
+	attrSearches     = attribute.Key("typesense.searches")
+	attrAttempt      = attribute.Key("typesense.attempt")
+	attrNodeURL      = attribute.Key("typesense.node.url")
+	attrHedged       = attribute.Key("typesense.hedged")
+	attrRetryReason  = attribute.Key("typesense.retry_reason")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// Option configures the tracing of a client.
+type Option func(*config)
+
+// WithTracerProvider sets the TracerProvider used to create the spans.
+// By default, the global TracerProvider is used.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tracerProvider
+	}
+}
+
+// WithPropagators sets the propagators used to inject the trace context into
+// the headers of the requests. By default, the global propagators are used.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = propagators
+	}
+}
+
+// WithTracing traces the calls made by the client and the attempts sent to the nodes.
+func WithTracing(opts ...Option) typesense.ClientOption {
+	t := newTracer(opts)
+	return func(c *typesense.Client) {
+		typesense.WithMiddleware(t.callMiddleware)(c)
+		typesense.WithAttemptMiddleware(t.attemptMiddleware)(c)
+	}
+}
+
+type tracer struct {
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+}
+
+func newTracer(opts []Option) *tracer {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return &tracer{
+		tracer:      c.tracerProvider.Tracer(instrumentationName),
+		propagators: c.propagators,
+	}
+}
+
+// callMiddleware traces a call made by the client, including all of its attempts.
+func (t *tracer) callMiddleware(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+	return circuit.HTTPRequestDoerFunc(func(req *http.Request) (*http.Response, error) {
+		operation := typesense.RequestOperation(req)
+		spanName := "typesense.request"
+		if operation.Name != "" {
+			spanName = "typesense." + operation.Name
+		}
+		attrs := []attribute.KeyValue{
+			attribute.String("db.system", "typesense"),
+			attrOperation.String(operation.Name),
+			attribute.String("http.request.method", req.Method),
+		}
+		if operation.Collection != "" {
+			attrs = append(attrs, attrCollection.String(operation.Collection))
+		}
+		if queryBy := req.URL.Query().Get("query_by"); queryBy != "" {
+			attrs = append(attrs, attrQueryBy.String(queryBy))
+		}
+
+		ctx, span := t.tracer.Start(req.Context(), spanName,
+			trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		response, err := next.Do(req.WithContext(ctx))
+		if err != nil {
+			recordError(span, err)
+			return response, err
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+
+		switch {
+		case response.StatusCode >= 400:
+			body, err := bufferBody(response)
+			if err != nil {
+				return response, err
+			}
+			recordError(span, typesense.NewHTTPError(response, body))
+		case operation.Name == "documents.search" || operation.Name == "multi_search":
+			body, err := bufferBody(response)
+			if err != nil {
+				return response, err
+			}
+			span.SetAttributes(searchResultAttributes(body)...)
+		}
+		return response, nil
+	})
+}
+
+// attemptMiddleware traces an attempt sent to a node.
+func (t *tracer) attemptMiddleware(next circuit.HTTPRequestDoer) circuit.HTTPRequestDoer {
+	return circuit.HTTPRequestDoerFunc(func(req *http.Request) (*http.Response, error) {
+		info, ok := typesense.AttemptInfoFromContext(req.Context())
+		if !ok {
+			// background health probes are not part of a call
+			return next.Do(req)
+		}
+		attrs := []attribute.KeyValue{
+			attrAttempt.Int(info.Number),
+			attrNodeURL.String(info.NodeURL),
+			attrHedged.Bool(info.Hedged),
+		}
+		if info.RetryReason != "" {
+			attrs = append(attrs, attrRetryReason.String(info.RetryReason))
+		}
+
+		ctx, span := t.tracer.Start(req.Context(), "typesense.attempt",
+			trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		req = req.WithContext(ctx)
+		req.Header = req.Header.Clone()
+		t.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		response, err := next.Do(req)
+		if err != nil {
+			recordError(span, err)
+			return response, err
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+		if response.StatusCode >= 400 {
+			span.SetStatus(codes.Error, "status "+strconv.Itoa(response.StatusCode))
+		}
+		return response, nil
+	})
+}
+
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// bufferBody reads the response body and replaces it with the bytes read,
+// so that it can still be read by the client.
+func bufferBody(response *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+type searchResult struct {
+	Found        *int              `json:"found"`
+	Hits         []json.RawMessage `json:"hits"`
+	GroupedHits  []json.RawMessage `json:"grouped_hits"`
+	SearchTimeMs *int              `json:"search_time_ms"`
+}
+
+func searchResultAttributes(body []byte) []attribute.KeyValue {
+	var result struct {
+		searchResult
+		Results []searchResult `json:"results"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil
+	}
+	if result.Results != nil {
+		// a multi search is summed up over its searches
+		found, searchTimeMs := 0, 0
+		for _, r := range result.Results {
+			if r.Found != nil {
+				found += *r.Found
+			}
+			if r.SearchTimeMs != nil {
+				searchTimeMs = max(searchTimeMs, *r.SearchTimeMs)
+			}
+		}
+		return []attribute.KeyValue{
+			attrSearches.Int(len(result.Results)),
+			attrFound.Int(found),
+			attrSearchTimeMs.Int(searchTimeMs),
+		}
+	}
+	var attrs []attribute.KeyValue
+	if result.Found != nil {
+		attrs = append(attrs, attrFound.Int(*result.Found))
+	}
+	if result.GroupedHits != nil {
+		attrs = append(attrs, attrHits.Int(len(result.GroupedHits)))
+	} else {
+		attrs = append(attrs, attrHits.Int(len(result.Hits)))
+	}
+	if result.SearchTimeMs != nil {
+		attrs = append(attrs, attrSearchTimeMs.Int(*result.SearchTimeMs))
+	}
+	return attrs
+}
@@ -0,0 +1,148 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracerProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestTracingSearchWithRetry(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	var traceparent string
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"found":42,"hits":[{"document":{}},{"document":{}}],"search_time_ms":3}`))
+	}))
+	defer healthy.Close()
+
+	tracerProvider, recorder := newTracerProvider()
+	client := typesense.NewClient(
+		typesense.WithNodes([]string{failing.URL, healthy.URL}),
+		typesense.WithRetryInterval(0),
+		WithTracing(WithTracerProvider(tracerProvider), WithPropagators(propagation.TraceContext{})),
+	)
+	defer client.Close()
+
+	ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "parent")
+	result, err := client.Collection("books").Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:       pointer.String("dune"),
+		QueryBy: pointer.String("title"),
+	})
+	parent.End()
+	assert.NoError(t, err)
+	assert.Equal(t, 42, *result.Found)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 4)
+	first, second, call := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "typesense.documents.search", call.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), call.Parent().SpanID())
+	callAttrs := spanAttributes(call)
+	assert.Equal(t, "books", callAttrs[attrCollection].AsString())
+	assert.Equal(t, "title", callAttrs[attrQueryBy].AsString())
+	assert.Equal(t, int64(42), callAttrs[attrFound].AsInt64())
+	assert.Equal(t, int64(2), callAttrs[attrHits].AsInt64())
+	assert.Equal(t, int64(3), callAttrs[attrSearchTimeMs].AsInt64())
+	assert.Equal(t, codes.Unset, call.Status().Code)
+
+	assert.Equal(t, "typesense.attempt", first.Name())
+	assert.Equal(t, call.SpanContext().SpanID(), first.Parent().SpanID())
+	firstAttrs := spanAttributes(first)
+	assert.Equal(t, int64(1), firstAttrs[attrAttempt].AsInt64())
+	assert.Equal(t, failing.URL, firstAttrs[attrNodeURL].AsString())
+	assert.Equal(t, codes.Error, first.Status().Code)
+
+	assert.Equal(t, call.SpanContext().SpanID(), second.Parent().SpanID())
+	secondAttrs := spanAttributes(second)
+	assert.Equal(t, int64(2), secondAttrs[attrAttempt].AsInt64())
+	assert.Equal(t, healthy.URL, secondAttrs[attrNodeURL].AsString())
+	assert.Equal(t, "status 503", secondAttrs[attrRetryReason].AsString())
+	assert.Contains(t, traceparent, second.SpanContext().SpanID().String())
+}
+
+func TestTracingRecordsHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer server.Close()
+
+	tracerProvider, recorder := newTracerProvider()
+	client := typesense.NewClient(
+		typesense.WithServer(server.URL),
+		WithTracing(WithTracerProvider(tracerProvider)),
+	)
+
+	_, err := client.Collection("books").Retrieve(context.Background())
+	assert.ErrorIs(t, err, typesense.ErrNotFound)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	call := spans[1]
+	assert.Equal(t, "typesense.collection.retrieve", call.Name())
+	assert.Equal(t, codes.Error, call.Status().Code)
+	// the span records the error the client returns
+	assert.Equal(t, err.Error(), call.Status().Description)
+	assert.Len(t, call.Events(), 1)
+	assert.Equal(t, "exception", call.Events()[0].Name)
+	assert.Contains(t, call.Events()[0].Attributes, attribute.String("exception.type", "*typesense.HTTPError"))
+}
+
+func TestTracingRecordsTransportError(t *testing.T) {
+	tracerProvider, recorder := newTracerProvider()
+	errTransport := errors.New("connection refused")
+	client := typesense.NewClient(
+		typesense.WithServer("http://localhost:8108"),
+		typesense.WithCustomHTTPClient(&http.Client{Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errTransport
+		})}),
+		WithTracing(WithTracerProvider(tracerProvider)),
+	)
+
+	_, err := client.Collections().Retrieve(context.Background(), nil)
+	assert.ErrorIs(t, err, errTransport)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, codes.Error, span.Status().Code)
+	}
+	assert.Equal(t, "typesense.collections.retrieve", spans[1].Name())
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,133 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"strings"
+
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+)
+
+// TypedDocumentsInterface is a type for Documents API operations on documents of type T
+type TypedDocumentsInterface[T any] interface {
+	// Create returns indexed document
+	Create(ctx context.Context, document T, params *api.DocumentIndexParameters) (T, error)
+	// Update updates documents matching the filter_by condition
+	Update(ctx context.Context, updateFields interface{}, params *api.UpdateDocumentsParams) (int, error)
+	// Upsert returns indexed/updated document
+	Upsert(ctx context.Context, document T, params *api.DocumentIndexParameters) (T, error)
+	// Delete returns number of deleted documents
+	Delete(ctx context.Context, filter *api.DeleteDocumentsParams) (int, error)
+	// Search performs document search in collection and decodes the hits into T
+	Search(ctx context.Context, params *api.SearchCollectionParams) (*SearchResult[T], error)
+	// Pages searches page by page, fetching the next page only once the
+	// previous one has been handled, until all hits have been found or a limit
+	// set by WithSearchMaxHits or WithSearchLimitHits is reached. The pages are
+	// fetched by offset in pages of per_page hits, or MaxSearchPerPage by
+	// default, unless WithSearchKeyset is used.
+	Pages(ctx context.Context, params *api.SearchCollectionParams, opts ...SearchOption) iter.Seq2[*SearchResult[T], error]
+	// SearchAll yields the hits of the pages of Pages one at a time. Grouped
+	// hits are not yielded, use Pages for grouped searches.
+	SearchAll(ctx context.Context, params *api.SearchCollectionParams, opts ...SearchOption) iter.Seq2[Hit[T], error]
+	// Export returns all documents from index
+	Export(ctx context.Context, params *api.ExportDocumentsParams) ([]T, error)
+	// ExportSeq yields the documents from index one at a time, see ExportSeq
+	ExportSeq(ctx context.Context, params *api.ExportDocumentsParams) iter.Seq2[T, error]
+	// Import returns json array. Each item of the response indicates
+	// the result of each document present in the request body (in the same order).
+	Import(ctx context.Context, documents []T, params *api.ImportDocumentsParams) ([]*api.ImportDocumentResponse, error)
+	// ImportSeq imports the documents of the sequence in chunks of WithImportChunkSize
+	// documents or WithImportChunkBytes bytes, so that only one chunk is held in
+	// memory, and yields the result of each document as it arrives. The documents
+	// are imported while the results are ranged over; breaking out of the loop
+	// stops the import.
+	ImportSeq(ctx context.Context, documents iter.Seq[T], params *api.ImportDocumentsParams, opts ...ImportOption) iter.Seq2[*api.ImportDocumentResponse, error]
+	// ImportChan imports the documents received from the channel like ImportSeq
+	// until the channel is closed or ctx is done.
+	ImportChan(ctx context.Context, documents <-chan T, params *api.ImportDocumentsParams, opts ...ImportOption) iter.Seq2[*api.ImportDocumentResponse, error]
+	// ImportWithRetry imports the documents like ImportSeq and imports the ones
+	// that failed transiently again with backoff, see WithImportMaxRetries and
+	// WithImportBackoff. The documents that could not be imported are returned
+	// in the report, classified by ClassifyImportError.
+	ImportWithRetry(ctx context.Context, documents []T, params *api.ImportDocumentsParams, opts ...ImportOption) (*ImportReport[T], error)
+}
+
+var _ TypedDocumentsInterface[any] = (*typedDocuments[any])(nil)
+
+// typedDocuments is internal implementation of TypedDocumentsInterface
+type typedDocuments[T any] struct {
+	documents
+}
+
+// decodeJSONResponse decodes the body of a response with the expected status into v.
+func decodeJSONResponse(response *http.Response, expectedStatus int, v any) error {
+	defer response.Body.Close()
+	if !strings.Contains(response.Header.Get("Content-Type"), "json") || response.StatusCode != expectedStatus {
+		body, _ := io.ReadAll(response.Body)
+		return newHTTPError(response, body)
+	}
+	return json.NewDecoder(response.Body).Decode(v)
+}
+
+func (d *typedDocuments[T]) indexDocument(ctx context.Context, document T, params *api.IndexDocumentParams) (resp T, err error) {
+	response, err := d.apiClient.IndexDocument(ctx, d.collectionName, params, document)
+	if err != nil {
+		return resp, err
+	}
+	err = decodeJSONResponse(response, http.StatusCreated, &resp)
+	return resp, err
+}
+
+func (d *typedDocuments[T]) Create(ctx context.Context, document T, params *api.DocumentIndexParameters) (T, error) {
+	return d.indexDocument(ctx, document, &api.IndexDocumentParams{DirtyValues: params.DirtyValues})
+}
+
+func (d *typedDocuments[T]) Upsert(ctx context.Context, document T, params *api.DocumentIndexParameters) (T, error) {
+	return d.indexDocument(ctx, document, &api.IndexDocumentParams{Action: pointer.Any(api.Upsert), DirtyValues: params.DirtyValues})
+}
+
+func (d *typedDocuments[T]) Search(ctx context.Context, params *api.SearchCollectionParams) (*SearchResult[T], error) {
+	response, err := d.apiClient.SearchCollection(ctx, d.collectionName, params)
+	if err != nil {
+		return nil, err
+	}
+	var result SearchResult[T]
+	if err := decodeJSONResponse(response, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (d *typedDocuments[T]) Export(ctx context.Context, params *api.ExportDocumentsParams) ([]T, error) {
+	body, err := d.documents.Export(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var result []T
+	// the documents are exported in jsonl format, i.e. a stream of json values
+	decoder := json.NewDecoder(body)
+	for {
+		var doc T
+		if err := decoder.Decode(&doc); errors.Is(err, io.EOF) {
+			return result, nil
+		} else if err != nil {
+			return result, err
+		}
+		result = append(result, doc)
+	}
+}
+
+func (d *typedDocuments[T]) Import(ctx context.Context, documents []T, params *api.ImportDocumentsParams) ([]*api.ImportDocumentResponse, error) {
+	docs := make([]interface{}, len(documents))
+	for i, doc := range documents {
+		docs[i] = doc
+	}
+	return d.documents.Import(ctx, docs, params)
+}
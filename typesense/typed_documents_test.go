@@ -0,0 +1,171 @@
+package typesense
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/typesense/typesense-go/v4/typesense/api"
+	"github.com/typesense/typesense-go/v4/typesense/api/pointer"
+)
+
+type companyDocument struct {
+	ID           string `json:"id"`
+	CompanyName  string `json:"company_name"`
+	NumEmployees int    `json:"num_employees"`
+}
+
+func TestTypedDocumentsCreate(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		validateRequestMetadata(t, r, "/collections/companies/documents", http.MethodPost)
+		assert.Empty(t, r.URL.Query().Get("action"))
+		var doc companyDocument
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&doc))
+		assert.Equal(t, companyDocument{ID: "123", CompanyName: "Stark Industries", NumEmployees: 5215}, doc)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(jsonEncode(t, doc))
+	})
+	defer server.Close()
+
+	result, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().Create(context.Background(),
+		companyDocument{ID: "123", CompanyName: "Stark Industries", NumEmployees: 5215}, &api.DocumentIndexParameters{})
+	assert.NoError(t, err)
+	assert.Equal(t, companyDocument{ID: "123", CompanyName: "Stark Industries", NumEmployees: 5215}, result)
+}
+
+func TestTypedDocumentsUpsert(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		validateRequestMetadata(t, r, "/collections/companies/documents", http.MethodPost)
+		assert.Equal(t, "upsert", r.URL.Query().Get("action"))
+		assert.Equal(t, "coerce_or_drop", r.URL.Query().Get("dirty_values"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		io.Copy(w, r.Body)
+	})
+	defer server.Close()
+
+	result, err := GenericCollection[*companyDocument](client, "companies").TypedDocuments().Upsert(context.Background(),
+		&companyDocument{ID: "123", CompanyName: "Stark Industries"},
+		&api.DocumentIndexParameters{DirtyValues: pointer.Any(api.CoerceOrDrop)})
+	assert.NoError(t, err)
+	assert.Equal(t, &companyDocument{ID: "123", CompanyName: "Stark Industries"}, result)
+}
+
+func TestTypedDocumentsCreateOnHttpStatusErrorCodeReturnsError(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"message":"A document with id 123 already exists."}`))
+	})
+	defer server.Close()
+
+	_, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().Create(context.Background(),
+		companyDocument{ID: "123"}, &api.DocumentIndexParameters{})
+	assert.ErrorIs(t, err, ErrConflict)
+	var httpErr *HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, "A document with id 123 already exists.", httpErr.Message)
+}
+
+func TestTypedDocumentsSearch(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		validateRequestMetadata(t, r, "/collections/companies/documents/search", http.MethodGet)
+		assert.Equal(t, "stark", r.URL.Query().Get("q"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"found": 1,
+			"out_of": 10,
+			"page": 1,
+			"search_time_ms": 2,
+			"hits": [{
+				"document": {"id": "123", "company_name": "Stark Industries", "num_employees": 5215},
+				"highlight": {"company_name": {"matched_tokens": ["Stark"], "snippet": "<mark>Stark</mark> Industries"}},
+				"highlights": [{"field": "company_name", "matched_tokens": ["Stark"], "snippet": "<mark>Stark</mark> Industries"}],
+				"text_match": 578730123365187705,
+				"text_match_info": {"best_field_score": "1108091338752", "fields_matched": 1, "tokens_matched": 1}
+			}]
+		}`))
+	})
+	defer server.Close()
+
+	result, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().Search(context.Background(),
+		&api.SearchCollectionParams{Q: pointer.String("stark"), QueryBy: pointer.String("company_name")})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *result.Found)
+	assert.Equal(t, 2, *result.SearchTimeMs)
+	assert.Nil(t, result.SearchResult.Hits)
+	assert.Len(t, result.Hits, 1)
+
+	hit := result.Hits[0]
+	assert.Equal(t, companyDocument{ID: "123", CompanyName: "Stark Industries", NumEmployees: 5215}, hit.Document)
+	assert.Nil(t, hit.SearchResultHit.Document)
+	assert.Equal(t, int64(578730123365187705), *hit.TextMatch)
+	assert.Equal(t, 1, *hit.TextMatchInfo.FieldsMatched)
+	assert.Equal(t, "<mark>Stark</mark> Industries", *(*hit.Highlights)[0].Snippet)
+	var highlight struct {
+		CompanyName HighlightField `json:"company_name"`
+	}
+	assert.NoError(t, hit.DecodeHighlight(&highlight))
+	assert.Equal(t, HighlightField{MatchedTokens: []string{"Stark"}, Snippet: "<mark>Stark</mark> Industries"}, highlight.CompanyName)
+}
+
+func TestTypedDocumentsSearchOnHttpStatusErrorCodeReturnsError(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not found."}`))
+	})
+	defer server.Close()
+
+	_, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().Search(context.Background(),
+		&api.SearchCollectionParams{Q: pointer.String("stark")})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestTypedDocumentsExport(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		validateRequestMetadata(t, r, "/collections/companies/documents/export", http.MethodGet)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte("{\"id\":\"1\",\"company_name\":\"Stark Industries\"}\n{\"id\":\"2\",\"company_name\":\"Wayne Enterprises\"}"))
+	})
+	defer server.Close()
+
+	result, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().Export(context.Background(),
+		&api.ExportDocumentsParams{})
+	assert.NoError(t, err)
+	assert.Equal(t, []companyDocument{
+		{ID: "1", CompanyName: "Stark Industries"},
+		{ID: "2", CompanyName: "Wayne Enterprises"},
+	}, result)
+}
+
+func TestTypedDocumentsImport(t *testing.T) {
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		validateRequestMetadata(t, r, "/collections/companies/documents/import", http.MethodPost)
+		assert.Equal(t, "upsert", r.URL.Query().Get("action"))
+		var docs []companyDocument
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var doc companyDocument
+			assert.NoError(t, json.Unmarshal(scanner.Bytes(), &doc))
+			docs = append(docs, doc)
+		}
+		assert.Equal(t, []companyDocument{{ID: "1"}, {ID: "2"}}, docs)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("{\"success\":true}\n{\"success\":false,\"error\":\"Bad JSON.\"}"))
+	})
+	defer server.Close()
+
+	result, err := GenericCollection[companyDocument](client, "companies").TypedDocuments().Import(context.Background(),
+		[]companyDocument{{ID: "1"}, {ID: "2"}}, &api.ImportDocumentsParams{Action: pointer.Any(api.Upsert)})
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.ImportDocumentResponse{
+		{Success: true},
+		{Success: false, Error: "Bad JSON."},
+	}, result)
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,6 +17,29 @@ func newTestServerAndClient(handler func(w http.ResponseWriter, r *http.Request)
 	return server, NewClient(WithServer(server.URL))
 }
 
+// newRecordingTestClient returns a client whose requests are recorded with
+// record and then answered by respond, together with the number of the
+// request, and a function returning the values recorded so far. The server is
+// closed when the test ends.
+func newRecordingTestClient[R any](t *testing.T, record func(r *http.Request) R, respond func(w http.ResponseWriter, r *http.Request, request int, recorded R)) (*Client, func() []R) {
+	var mu sync.Mutex
+	var recorded []R
+	server, client := newTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		value := record(r)
+		mu.Lock()
+		recorded = append(recorded, value)
+		request := len(recorded)
+		mu.Unlock()
+		respond(w, r, request, value)
+	})
+	t.Cleanup(server.Close)
+	return client, func() []R {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(recorded)
+	}
+}
+
 func validateRequestMetadata(t *testing.T, r *http.Request, expectedEndpoint string, expectedMethod string) {
 	if strings.Contains(expectedEndpoint, "?") {
 		if r.URL.String() != expectedEndpoint {